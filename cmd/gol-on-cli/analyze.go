@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/cli"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/pattern"
+)
+
+type analyzeReport struct {
+	Name string `json:"name,omitempty"`
+	Rule string `json:"rule"`
+	analysis.Result
+}
+
+func runAnalyze(ctx *cli.Context) int {
+	stdin, stdout, stderr, flags := ctx.Stdin, ctx.Stdout, ctx.Stderr, ctx.Flags
+
+	from := flags.String("from", "", "input format: rle, cells or life106 (default from extension, else detected)")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	ruleText := flags.String("rule", "", "rule to run, e.g. B36/S23 (default from the pattern, else B3/S23)")
+	generations := flags.Int("generations", analysis.DefaultMaxGenerations, "give up after n generations")
+	maxPeriod := flags.Int("max-period", analysis.DefaultMaxPeriod, "longest period to look for")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if flags.NArg() != 1 {
+		return ctx.UsageError("expected one pattern file, URL or -")
+	}
+	if *generations <= 0 || *maxPeriod <= 0 {
+		fmt.Fprintln(stderr, "failed to analyze: generations and max-period must be greater than zero")
+		return 1
+	}
+
+	source := flags.Arg(0)
+	format := pattern.PatternFormat("")
+	if *from != "" {
+		parsed, err := pattern.ParseFormat(*from)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to analyze: %v\n", err)
+			return 1
+		}
+		format = parsed
+	} else if detected, ok := pattern.FormatFromPath(source); ok {
+		format = detected
+	}
+	content, err := readPatternSource(source, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to analyze: %v\n", err)
+		return 1
+	}
+	decoded, err := pattern.Decode(format, content)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to analyze: %v\n", err)
+		return 1
+	}
+
+	options := analysis.DefaultOptions()
+	options.MaxGenerations = *generations
+	options.MaxPeriod = *maxPeriod
+	if *ruleText == "" {
+		*ruleText = decoded.Rule
+	}
+	if *ruleText != "" {
+		rule, err := engine.ParseRule(*ruleText)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to analyze: %v\n", err)
+			return 1
+		}
+		options.Rule = rule
+	}
+
+	report := analyzeReport{
+		Name:   decoded.Name,
+		Rule:   options.Rule.String(),
+		Result: analysis.Analyze(engine.UniverseFromBoard(decoded.Board, decoded.X, decoded.Y), options),
+	}
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeAnalyzeReport(stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to analyze: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func writeAnalyzeReport(w io.Writer, report analyzeReport) error {
+	var b strings.Builder
+	if report.Name != "" {
+		fmt.Fprintf(&b, "name: %s\n", report.Name)
+	}
+	fmt.Fprintf(&b, "rule: %s\n", report.Rule)
+	b.WriteString(report.Result.String())
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// readPatternSource reads a file, stdin for "-", or a ConwayLife wiki page.
+func readPatternSource(source string, stdin io.Reader) (string, error) {
+	if source == "-" {
+		content, err := io.ReadAll(stdin)
+		return string(content), err
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		if !pattern.ValidateWikiURL(source) {
+			return "", fmt.Errorf("invalid pattern-url: must match https://conwaylife.com/wiki/...")
+		}
+		return pattern.NewHTTPWikiLoader(startupPatternTimeout, startupPatternMaxSize).Load(source)
+	}
+	content, err := os.ReadFile(source)
+	return string(content), err
+}
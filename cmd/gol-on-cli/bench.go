@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gol-on-cli/internal/bench"
+	"gol-on-cli/internal/cli"
+)
+
+// runBench times each engine on random soups of every size and density
+// given.
+func runBench(ctx *cli.Context) int {
+	stdout, stderr, flags := ctx.Stdout, ctx.Stderr, ctx.Flags
+
+	enginesText := flags.String("engines", strings.Join(bench.Engines(), ","), "engines to time: board, universe")
+	sizesText := flags.String("sizes", "64x64,256x256", "soup sizes: comma-separated WxH or N")
+	densitiesText := flags.String("densities", "0.2,0.5", "soup densities: comma-separated fractions (0-1)")
+	benchtime := flags.Duration("benchtime", time.Second, "how long to run each case")
+	generations := flags.Int("generations", 0, "run each case for exactly n generations instead of --benchtime")
+	count := flags.Int("count", 1, "run each case n times")
+	seed := flags.Int64("seed", 1, "random seed for the soups")
+	format := flags.String("format", bench.FormatText, "output format: text, json or benchstat")
+	out := flags.String("out", "", "write results to a file instead of stdout")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if flags.NArg() > 0 {
+		return ctx.UsageError("unexpected argument %q", flags.Arg(0))
+	}
+
+	engines, err := bench.ParseEngines(*enginesText)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+		return 1
+	}
+	sizes, err := bench.ParseSizes(*sizesText)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+		return 1
+	}
+	densities, err := bench.ParseDensities(*densitiesText)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+		return 1
+	}
+	outputFormat, err := bench.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+		return 1
+	}
+	if *benchtime <= 0 || *generations < 0 || *count <= 0 {
+		fmt.Fprintln(stderr, "failed to bench: benchtime and count must be greater than zero, generations zero or greater")
+		return 1
+	}
+
+	options := bench.Options{Seed: *seed, Duration: *benchtime, Generations: *generations}
+	var results []bench.Result
+	for _, c := range bench.Cases(engines, sizes, densities) {
+		for i := 0; i < *count; i++ {
+			result, err := bench.Run(c, options)
+			if err != nil {
+				fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+				return 1
+			}
+			results = append(results, result)
+		}
+	}
+
+	report := bench.NewReport(*seed, results)
+	if *out == "" {
+		err = report.Write(stdout, outputFormat)
+	} else {
+		var file *os.File
+		if file, err = os.Create(*out); err == nil {
+			err = report.Write(file, outputFormat)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to bench: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/app"
+)
+
+type censusResult struct {
+	restart int
+	census  analysis.Census
+}
+
+// startCensus sends the census of the soup the restart policy just ended to
+// results from a goroutine. It reports whether a soup had finished.
+func startCensus(sim *app.Simulation, env *commandEnv, results chan<- censusResult) bool {
+	board, _, ok := sim.FinishedSoup()
+	if !ok {
+		return false
+	}
+	board, options, restart := board.Clone(), env.censusOptions(sim), sim.AutoRestarts()
+	go func() {
+		results <- censusResult{restart: restart, census: analysis.TakeCensus(board, options)}
+	}()
+	return true
+}
+
+func recordCensus(env *commandEnv, census analysis.Census) string {
+	env.census = &census
+	return "census:" + census.String()
+}
+
+func (env *commandEnv) censusOptions(sim *app.Simulation) analysis.CensusOptions {
+	options := analysis.DefaultCensusOptions()
+	options.Rule = sim.Rule()
+	if env.neighborhood.Range > 0 {
+		options.Neighborhood = env.neighborhood
+	}
+	return options
+}
+
+// exportCensus writes the census of the last finished soup, or of the
+// current board before any soup has finished, as CSV or JSON.
+func exportCensus(sim *app.Simulation, env *commandEnv, path string) error {
+	var census analysis.Census
+	if env.census != nil {
+		census = *env.census
+	} else {
+		census = analysis.TakeCensus(sim.Board(), env.censusOptions(sim))
+	}
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension != ".csv" && extension != ".json" {
+		return fmt.Errorf("unknown census format %q (available: .csv, .json)", filepath.Ext(path))
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if extension == ".csv" {
+		err = census.WriteCSV(file)
+	} else {
+		err = census.WriteJSON(file)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/input"
+	"gol-on-cli/internal/pattern"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type commandEnv struct {
+	speed        *speedControl
+	source       string
+	patternURL   string
+	neighborhood analysis.Neighborhood
+	census       *analysis.Census
+}
+
+var pathCommands = map[string]bool{"load": true, "save": true, "census": true}
+
+// executeCommand returns the notice to show and whether the board was
+// replaced rather than evolved.
+func executeCommand(sim *app.Simulation, env *commandEnv, line string) (notice string, redraw bool) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if name == "" {
+		return "", false
+	}
+	arg = strings.TrimSpace(arg)
+	if arg == "" || !pathCommands[name] && len(strings.Fields(arg)) > 1 {
+		return fmt.Sprintf("usage: :%s <value>", name), false
+	}
+
+	switch name {
+	case "load":
+		if err := loadPatternArgument(sim, arg); err != nil {
+			return fmt.Sprintf("load-failed: %v", err), false
+		}
+		env.source = arg
+		env.patternURL = arg
+		return "loaded:" + arg, true
+	case "rule":
+		rule, err := engine.ParseRule(arg)
+		if err != nil {
+			return fmt.Sprintf("rule-failed: %v", err), false
+		}
+		sim.SetRule(rule)
+		return "rule:" + rule.String(), false
+	case "fps":
+		fps, err := strconv.Atoi(arg)
+		if err != nil || fps <= 0 {
+			return "fps-failed: must be greater than zero", false
+		}
+		env.speed.SetFPS(fps)
+		return fmt.Sprintf("fps:%d", env.speed.fps), false
+	case "seed":
+		sim.ReseedString(arg)
+		env.source = "random"
+		return "seed:" + arg, true
+	case "save":
+		if err := os.WriteFile(arg, []byte(pattern.EncodeRLEWithRule(sim.Board(), sim.Rule().String())), 0o644); err != nil {
+			return fmt.Sprintf("save-failed: %v", err), false
+		}
+		return "saved:" + arg, false
+	case "census":
+		if err := exportCensus(sim, env, arg); err != nil {
+			return fmt.Sprintf("census-failed: %v", err), false
+		}
+		return "census-saved:" + arg, false
+	case "goto":
+		generation, err := strconv.Atoi(arg)
+		if err != nil {
+			return "goto-failed: generation must be an integer", false
+		}
+		if err := sim.Seek(generation); err != nil {
+			return fmt.Sprintf("goto-failed: %v", err), true
+		}
+		return fmt.Sprintf("generation:%d", sim.Generation()), true
+	}
+	return fmt.Sprintf("unknown-command: %s", name), false
+}
+
+func loadPatternArgument(sim *app.Simulation, arg string) error {
+	if strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
+		if !pattern.ValidateWikiURL(arg) {
+			return fmt.Errorf("must match https://conwaylife.com/wiki/...")
+		}
+		return tryLoadPatternForSimulation(sim, arg)
+	}
+	content, err := os.ReadFile(arg)
+	if err != nil {
+		return err
+	}
+	rule, hasRule := sim.Rule(), false
+	if text, ok := pattern.RuleFromRLE(string(content)); ok {
+		if rule, err = engine.ParseRule(text); err != nil {
+			return err
+		}
+		hasRule = true
+	}
+	if err := sim.LoadPatternFromWikiContent(string(content)); err != nil {
+		return err
+	}
+	if hasRule {
+		sim.SetRule(rule)
+	}
+	return nil
+}
+
+func handleCommandKey(commands *input.CommandLine, ev *tcell.EventKey) (string, bool) {
+	if ev.Key() == tcell.KeyRune {
+		return commands.HandleKey("", ev.Rune())
+	}
+	return commands.HandleKey(mapKeyEvent(ev), 0)
+}
+
+func commandStatus(commands *input.CommandLine) string {
+	status := ":" + commands.Text()
+	if len(commands.Candidates) > 0 {
+		status += "    " + strings.Join(commands.Candidates, " ")
+	}
+	return status
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/cli"
+)
+
+const configFileName = "config.toml"
+
+// configFlags maps command-line flags onto config keys. --seed-string wins
+// over --seed because flag.Visit goes in name order.
+var configFlags = map[string]string{
+	"fps":                 "fps",
+	"seed":                "seed",
+	"seed-string":         "seed",
+	"trail":               "trail",
+	"color":               "color",
+	"soup-density":        "soup.density",
+	"soup-size":           "soup.size",
+	"soup-placement":      "soup.placement",
+	"soup-symmetry":       "soup.symmetry",
+	"restart":             "restart.on",
+	"restart-linger":      "restart.linger",
+	"restart-population":  "restart.population",
+	"restart-generations": "restart.generations",
+	"restart-fade":        "restart.fade",
+	"census-neighborhood": "census.neighborhood",
+}
+
+// resolveConfig layers defaults, the config file, GOL_* variables and
+// passed flags, in that order. origins records which layer set each key.
+func resolveConfig(path string, flags *flag.FlagSet, lookupEnv func(string) (string, bool)) (app.Config, map[string]string, error) {
+	config := app.DefaultConfig()
+	origins := map[string]string{}
+
+	path, keys, err := loadConfig(path, &config)
+	if err != nil {
+		return app.Config{}, nil, err
+	}
+	for _, key := range keys {
+		origins[key] = path
+	}
+
+	keys, err = config.ApplyEnv(lookupEnv)
+	if err != nil {
+		return app.Config{}, nil, err
+	}
+	for _, key := range keys {
+		origins[key] = app.ConfigEnvName(key)
+	}
+
+	if err := applyConfigFlags(flags, &config, origins); err != nil {
+		return app.Config{}, nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return app.Config{}, nil, err
+	}
+	return config, origins, nil
+}
+
+// loadConfig applies the config file at path, or the default one when path
+// is empty; a missing default file is not an error.
+func loadConfig(path string, config *app.Config) (string, []string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath(configFileName)
+	}
+	if path == "" {
+		return "", nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return path, nil, nil
+		}
+		return "", nil, err
+	}
+	defer file.Close()
+	keys, err := config.ApplyTOML(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return path, keys, nil
+}
+
+func applyConfigFlags(flags *flag.FlagSet, config *app.Config, origins map[string]string) error {
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		key, ok := configFlags[f.Name]
+		if !ok || err != nil {
+			return
+		}
+		if setErr := config.Set(key, f.Value.String()); setErr != nil {
+			err = fmt.Errorf("--%s: %v", f.Name, setErr)
+			return
+		}
+		origins[key] = "--" + f.Name
+	})
+	return err
+}
+
+// runConfig handles "gol-on-cli config print [run options]".
+func runConfig(ctx *cli.Context) int {
+	f := defineRunFlags(ctx.Flags)
+	action := ""
+	if len(ctx.Args) > 0 && !strings.HasPrefix(ctx.Args[0], "-") {
+		action, ctx.Args = ctx.Args[0], ctx.Args[1:]
+	}
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if action != "print" || ctx.Flags.NArg() > 0 {
+		return ctx.UsageError("expected \"print\"")
+	}
+	config, origins, err := resolveConfig(*f.configPath, ctx.Flags, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(ctx.Stderr, "invalid config: %v\n", err)
+		return cli.ExitFailure
+	}
+	if err := config.WriteTOML(ctx.Stdout, origins); err != nil {
+		fmt.Fprintf(ctx.Stderr, "failed to print config: %v\n", err)
+		return cli.ExitFailure
+	}
+	return cli.ExitOK
+}
+
+func startSoupID(seed, soup string) (string, error) {
+	if soup != "" {
+		if _, _, err := app.ParseSoupID(soup); err != nil {
+			return "", err
+		}
+		return soup, nil
+	}
+	return app.SoupID(seed, 0), nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/cli"
+	"gol-on-cli/internal/pattern"
+)
+
+// runConvert rewrites a pattern file in another format. "-" or a missing
+// path means stdin/stdout.
+func runConvert(ctx *cli.Context) int {
+	stdin, stdout, stderr, flags := ctx.Stdin, ctx.Stdout, ctx.Stderr, ctx.Flags
+
+	from := flags.String("from", "", "input format: rle, cells or life106 (default from extension, else detected)")
+	to := flags.String("to", "", "output format: rle, cells or life106 (default from extension, else rle)")
+	rotate := flags.Int("rotate", 0, "rotate clockwise by 0, 90, 180 or 270 degrees")
+	flip := flags.String("flip", "", "mirror: h (left-right) or v (top-bottom)")
+	translate := flags.String("translate", "", "move the pattern origin by dx,dy")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if flags.NArg() > 2 {
+		return ctx.UsageError("expected at most an input and an output file")
+	}
+	in, out := flags.Arg(0), flags.Arg(1)
+
+	options, err := validateConvertOptions(in, out, *from, *to, *rotate, *flip, *translate)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to convert: %v\n", err)
+		return 1
+	}
+
+	var content []byte
+	if in == "" || in == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(in)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to convert: %v\n", err)
+		return 1
+	}
+	decoded, err := pattern.Decode(options.from, string(content))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to convert: %v\n", err)
+		return 1
+	}
+	encoded, err := pattern.Encode(options.apply(decoded), options.to)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to convert: %v\n", err)
+		return 1
+	}
+
+	if out == "" || out == "-" {
+		_, err = io.WriteString(stdout, encoded)
+	} else {
+		err = os.WriteFile(out, []byte(encoded), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to convert: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+type convertOptions struct {
+	from   pattern.PatternFormat
+	to     pattern.PatternFormat
+	turns  int
+	flip   string
+	dx, dy int
+}
+
+func validateConvertOptions(in, out, from, to string, rotate int, flip, translate string) (convertOptions, error) {
+	var options convertOptions
+	var err error
+	switch {
+	case from != "":
+		if options.from, err = pattern.ParseFormat(from); err != nil {
+			return convertOptions{}, err
+		}
+	case in != "" && in != "-":
+		options.from, _ = pattern.FormatFromPath(in)
+	}
+	switch {
+	case to != "":
+		if options.to, err = pattern.ParseFormat(to); err != nil {
+			return convertOptions{}, err
+		}
+	case out != "" && out != "-":
+		format, ok := pattern.FormatFromPath(out)
+		if !ok {
+			return convertOptions{}, fmt.Errorf("cannot tell the output format of %s: use --to", out)
+		}
+		options.to = format
+	default:
+		options.to = pattern.FormatRLE
+	}
+	if rotate%90 != 0 {
+		return convertOptions{}, fmt.Errorf("invalid rotate: must be a multiple of 90")
+	}
+	options.turns = (rotate/90%4 + 4) % 4
+	if flip != "" && flip != "h" && flip != "v" {
+		return convertOptions{}, fmt.Errorf("invalid flip %q: use h or v", flip)
+	}
+	options.flip = flip
+	if translate != "" {
+		dx, dy, found := strings.Cut(translate, ",")
+		x, errX := strconv.Atoi(strings.TrimSpace(dx))
+		y, errY := strconv.Atoi(strings.TrimSpace(dy))
+		if !found || errX != nil || errY != nil {
+			return convertOptions{}, fmt.Errorf("invalid translate %q: use dx,dy", translate)
+		}
+		options.dx, options.dy = x, y
+	}
+	return options, nil
+}
+
+// apply crops to the live cells, then flips and rotates the board in place
+// (its top-left corner stays put) and finally moves the origin.
+func (o convertOptions) apply(p pattern.Pattern) pattern.Pattern {
+	p = p.Cropped()
+	switch o.flip {
+	case "h":
+		p.Board = p.Board.FlipHorizontal()
+	case "v":
+		p.Board = p.Board.FlipVertical()
+	}
+	for i := 0; i < o.turns; i++ {
+		p.Board = p.Board.RotateClockwise()
+	}
+	p.X += o.dx
+	p.Y += o.dy
+	return p
+}
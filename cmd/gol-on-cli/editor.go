@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/input"
+	"gol-on-cli/internal/pattern"
+	"gol-on-cli/internal/renderer"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type editor struct {
+	clipboard    engine.Board
+	hasClipboard bool
+	lastPen      input.PenMode
+	lastButtons  tcell.ButtonMask
+	rng          *rand.Rand
+	osc52        io.Writer
+	notice       string
+}
+
+func newEditor(osc52 io.Writer) *editor {
+	return &editor{
+		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		osc52: osc52,
+	}
+}
+
+func applyEdit(state *input.State, sim *app.Simulation, ed *editor) {
+	board := sim.Board()
+	state.ClampCursor(board.Width(), board.Height())
+	if state.ConsumeToggleRequest() {
+		sim.Checkpoint()
+		sim.ToggleCell(state.CursorX, state.CursorY)
+	}
+	if state.Pen != input.PenNone && state.Pen != ed.lastPen {
+		sim.Checkpoint()
+	}
+	ed.lastPen = state.Pen
+	switch state.Pen {
+	case input.PenDraw:
+		sim.SetCell(state.CursorX, state.CursorY, true)
+	case input.PenErase:
+		sim.SetCell(state.CursorX, state.CursorY, false)
+	}
+	if state.Pasting && !ed.hasClipboard {
+		state.Pasting = false
+		ed.notice = "clipboard-empty"
+	}
+	applySelectionAction(state, sim, ed, state.ConsumeSelectionAction())
+}
+
+func applySelectionAction(state *input.State, sim *app.Simulation, ed *editor, action input.Action) {
+	if action == "" {
+		return
+	}
+	region := app.RegionFromCorners(state.Selection())
+	if action != input.ActionCopy && !state.Pasting {
+		sim.Checkpoint()
+	}
+	switch action {
+	case input.ActionCopy, input.ActionCut:
+		ed.clipboard = sim.CopyRegion(region)
+		ed.hasClipboard = true
+		if action == input.ActionCut {
+			sim.ClearRegion(region)
+		}
+		ed.notice = fmt.Sprintf("%s:%dx%d", action, region.Width, region.Height)
+		writeOSC52(ed.osc52, pattern.EncodeRLE(ed.clipboard))
+	case input.ActionPaste:
+		if ed.hasClipboard {
+			sim.Paste(ed.clipboard, state.CursorX, state.CursorY)
+		}
+	case input.ActionClear:
+		sim.ClearRegion(region)
+	case input.ActionFill:
+		sim.FillRegion(region)
+	case input.ActionRandomize:
+		sim.RandomizeRegion(region, ed.rng)
+	case input.ActionRotate, input.ActionFlipHorizontal, input.ActionFlipVertical:
+		if state.Pasting {
+			ed.clipboard = transformStamp(ed.clipboard, action)
+			return
+		}
+		stamp := transformStamp(sim.CopyRegion(region), action)
+		sim.ClearRegion(region)
+		sim.Paste(stamp, region.X, region.Y)
+		state.AnchorX, state.AnchorY = region.X, region.Y
+		state.CursorX, state.CursorY = region.X+stamp.Width()-1, region.Y+stamp.Height()-1
+		board := sim.Board()
+		state.ClampCursor(board.Width(), board.Height())
+	}
+}
+
+func transformStamp(stamp engine.Board, action input.Action) engine.Board {
+	switch action {
+	case input.ActionRotate:
+		return stamp.RotateClockwise()
+	case input.ActionFlipHorizontal:
+		return stamp.FlipHorizontal()
+	case input.ActionFlipVertical:
+		return stamp.FlipVertical()
+	}
+	return stamp
+}
+
+func writeOSC52(w io.Writer, text string) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+func handleMouseEvent(state *input.State, sim *app.Simulation, ed *editor, ev *tcell.EventMouse) {
+	x, y := ev.Position()
+	buttons := ev.Buttons()
+	pressed := buttons&(tcell.Button1|tcell.Button2) != 0 && ed.lastButtons == 0
+	ed.lastButtons = buttons
+	board := sim.Board()
+	if x < 0 || y < 0 || x >= board.Width() || y >= board.Height() {
+		return
+	}
+	if buttons&tcell.Button1 != 0 && ev.Modifiers()&tcell.ModShift != 0 {
+		if !state.Selecting {
+			state.BeginSelection(x, y)
+		}
+		state.MoveCursorTo(x, y)
+		return
+	}
+	state.MoveCursorTo(x, y)
+	if pressed && !state.Pasting {
+		sim.Checkpoint()
+	}
+	switch {
+	case buttons&tcell.Button1 != 0 && state.Pasting:
+		state.Pasting = false
+		applySelectionAction(state, sim, ed, input.ActionPaste)
+	case buttons&tcell.Button1 != 0:
+		sim.SetCell(x, y, true)
+	case buttons&tcell.Button2 != 0:
+		sim.SetCell(x, y, false)
+	}
+}
+
+func renderEditOverlay(screen tcell.Screen, state *input.State, ed *editor, board engine.Board, previous *engine.Board, palette renderer.Palette, trail *renderer.Trail) []cellCoord {
+	if !state.EditMode {
+		return nil
+	}
+	state.ClampCursor(board.Width(), board.Height())
+	drawn := make([]cellCoord, 0)
+	if state.Selecting {
+		region := app.RegionFromCorners(state.Selection())
+		for y := region.Y; y < region.Y+region.Height; y++ {
+			for x := region.X; x < region.X+region.Width; x++ {
+				mainc, _, style, _ := screen.GetContent(x, y)
+				screen.SetContent(x, y, mainc, nil, style.Background(paletteColor(palette, palette.RecentlyDead)))
+				drawn = append(drawn, cellCoord{x: x, y: y})
+			}
+		}
+	}
+	if state.Pasting && ed.hasClipboard {
+		ghost := tcell.StyleDefault.Foreground(paletteColor(palette, palette.Newborn))
+		for dy := 0; dy < ed.clipboard.Height(); dy++ {
+			for dx := 0; dx < ed.clipboard.Width(); dx++ {
+				x, y := state.CursorX+dx, state.CursorY+dy
+				if x >= board.Width() || y >= board.Height() {
+					continue
+				}
+				r := '·'
+				if ed.clipboard.IsAlive(dx, dy) {
+					r = '▒'
+				}
+				screen.SetContent(x, y, r, nil, ghost)
+				drawn = append(drawn, cellCoord{x: x, y: y})
+			}
+		}
+	}
+
+	x, y := state.CursorX, state.CursorY
+	mainc, _, style, _ := screen.GetContent(x, y)
+	screen.SetContent(x, y, mainc, nil, style.Reverse(true))
+	screen.ShowCursor(x, y)
+	return append(drawn, cellCoord{x: x, y: y})
+}
+
+func editStatus(state *input.State, ed *editor) string {
+	if !state.EditMode {
+		return ""
+	}
+	mode := "move"
+	switch {
+	case state.Pasting:
+		mode = fmt.Sprintf("paste[%dx%d]", ed.clipboard.Width(), ed.clipboard.Height())
+	case state.Selecting:
+		region := app.RegionFromCorners(state.Selection())
+		mode = fmt.Sprintf("select[%dx%d]", region.Width, region.Height)
+	case state.Pen == input.PenDraw:
+		mode = "draw"
+	case state.Pen == input.PenErase:
+		mode = "erase"
+	}
+	return fmt.Sprintf("%s@%d,%d", mode, state.CursorX, state.CursorY)
+}
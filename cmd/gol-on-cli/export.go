@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/cli"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/renderer"
+)
+
+const maxExportFrames = 10000
+
+func runExport(ctx *cli.Context) int {
+	stdout, stderr, flags := ctx.Stdout, ctx.Stderr, ctx.Flags
+
+	out := flags.String("out", "", "output file (.gif or .png)")
+	format := flags.String("format", "", "output format: gif or apng (default from extension)")
+	from := flags.Int("from", 0, "first generation to capture")
+	to := flags.Int("to", 50, "last generation to capture")
+	fps := flags.Int("fps", 5, "frames per second")
+	cellSize := flags.Int("cell-size", 8, "pixels per cell")
+	grid := flags.Bool("grid", false, "draw grid lines between cells")
+	seed := flags.Int64("seed", 0, "random seed")
+	width := flags.Int("width", 40, "board width")
+	height := flags.Int("height", 30, "board height")
+	patternURL := flags.String("pattern-url", "", "pattern URL to export instead of a random soup")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if *out == "" && flags.NArg() > 0 {
+		*out = flags.Arg(0)
+	}
+
+	options, err := validateExportOptions(*out, *format, *from, *to, *fps, *cellSize, *width, *height)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to export: %v\n", err)
+		return 1
+	}
+
+	sim := app.NewSimulation(*width, *height, *seed)
+	if *patternURL != "" {
+		if err := tryLoadPatternForSimulation(sim, *patternURL); err != nil {
+			fmt.Fprintf(stderr, "failed to export: %v\n", err)
+			return 1
+		}
+	}
+	frames := captureFrames(sim, *from, *to)
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to export: %v\n", err)
+		return 1
+	}
+	imageOptions := renderer.ImageOptions{
+		CellSize:  *cellSize,
+		GridLines: *grid,
+		Palette:   renderer.SelectPalette(true),
+	}
+	delay := time.Second / time.Duration(*fps)
+	if options == "apng" {
+		err = renderer.EncodeAPNG(file, frames, imageOptions, delay)
+	} else {
+		err = renderer.EncodeGIF(file, frames, imageOptions, delay)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to export: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "exported %d frames to %s\n", len(frames), *out)
+	return 0
+}
+
+func validateExportOptions(out, format string, from, to, fps, cellSize, width, height int) (string, error) {
+	if out == "" {
+		return "", fmt.Errorf("missing output file: use --out <file>")
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(out)) {
+		case ".png", ".apng":
+			format = "apng"
+		default:
+			format = "gif"
+		}
+	}
+	format = strings.ToLower(format)
+	if format != "gif" && format != "apng" {
+		return "", fmt.Errorf("invalid format: %s (must be gif or apng)", format)
+	}
+	if from < 0 || to < from {
+		return "", fmt.Errorf("invalid generation range: %d..%d", from, to)
+	}
+	if to-from+1 > maxExportFrames {
+		return "", fmt.Errorf("invalid generation range: at most %d frames", maxExportFrames)
+	}
+	if fps <= 0 {
+		return "", fmt.Errorf("invalid fps: must be greater than zero")
+	}
+	if cellSize <= 0 {
+		return "", fmt.Errorf("invalid cell-size: must be greater than zero")
+	}
+	if width <= 0 || height <= 0 {
+		return "", fmt.Errorf("invalid board size: width and height must be greater than zero")
+	}
+	return format, nil
+}
+
+func captureFrames(sim *app.Simulation, from, to int) []engine.Board {
+	frames := make([]engine.Board, 0, to-from+1)
+	for step := 0; step <= to; step++ {
+		if step > 0 {
+			sim.Tick()
+		}
+		if step >= from {
+			frames = append(frames, sim.Board())
+		}
+	}
+	return frames
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/renderer"
+)
+
+const cursorHomeSequence = "\x1b[H"
+
+type headlessOptions struct {
+	generations int
+	every       int
+	ansi        bool
+	cursorHome  bool
+	trueColor   bool
+	trail       int
+	source      string
+	stats       bool
+}
+
+func runHeadless(stdout io.Writer, sim *app.Simulation, options headlessOptions) error {
+	if options.every <= 0 {
+		return fmt.Errorf("invalid every: must be greater than zero")
+	}
+
+	palette := renderer.Palette{}
+	if options.ansi {
+		palette = renderer.SelectPalette(options.trueColor)
+	}
+	var trail *renderer.Trail
+	if options.trail > 0 {
+		trail = renderer.NewTrail(options.trail, 0, 0)
+		trail.Seed(sim.Board())
+	}
+
+	collector := app.NewStatsCollector(app.DefaultStatsHistory)
+	replaced := sim.Replaced()
+	var previous *engine.Board
+	for step := 0; step <= options.generations; step++ {
+		if step > 0 {
+			last := sim.Board()
+			previous = &last
+			sim.Tick()
+			if trail != nil {
+				trail.Advance(sim.Board())
+			}
+		}
+		if sim.Replaced() != replaced {
+			replaced = sim.Replaced()
+			collector.Reset()
+		}
+		stats := collector.Observe(sim.Board(), sim.Generation())
+		if step%options.every != 0 {
+			continue
+		}
+		current := sim.Board()
+		status := renderer.StatusBarData{Generation: sim.Generation(), PatternSource: options.source, SoupID: sim.SoupID()}
+		frame := renderer.BuildFrameWithTrail(current, previous, trail, status, palette)
+		if options.cursorHome {
+			frame = cursorHomeSequence + frame
+		}
+		if options.stats {
+			frame += "stats " + stats.String() + "\n"
+		}
+		if _, err := io.WriteString(stdout, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/input"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const keymapFileName = "keys.conf"
+
+var specialKeyNames = map[tcell.Key]string{
+	tcell.KeyUp:             "up",
+	tcell.KeyDown:           "down",
+	tcell.KeyLeft:           "left",
+	tcell.KeyRight:          "right",
+	tcell.KeyEnter:          "enter",
+	tcell.KeyEscape:         "esc",
+	tcell.KeyTab:            "tab",
+	tcell.KeyBackspace:      "backspace",
+	tcell.KeyBackspace2:     "backspace",
+	tcell.KeyDelete:         "delete",
+	tcell.KeyHome:           "home",
+	tcell.KeyEnd:            "end",
+	tcell.KeyPgUp:           "pgup",
+	tcell.KeyPgDn:           "pgdn",
+	tcell.KeyCtrlSpace:      "ctrl-space",
+	tcell.KeyCtrlUnderscore: "ctrl-_",
+}
+
+func handleKeyEvent(state *input.State, sim *app.Simulation, ed *editor, speed *speedControl, ev *tcell.EventKey) bool {
+	if ev.Key() == tcell.KeyCtrlC {
+		return true
+	}
+
+	key := mapKeyEvent(ev)
+	if key == "" {
+		return false
+	}
+
+	action := state.HandleKey(key)
+	if state.EditMode {
+		applyEdit(state, sim, ed)
+	}
+	switch action {
+	case input.ActionTogglePause:
+		if state.Paused {
+			sim.Pause()
+		} else {
+			sim.Resume()
+		}
+	case input.ActionRestart:
+		sim.Restart()
+	case input.ActionStepBack, input.ActionScrubBack:
+		state.Paused = true
+		sim.Pause()
+		steps := 1
+		if action == input.ActionScrubBack {
+			steps = timelineScrubStep
+		}
+		if sim.Rewind(steps) == 0 {
+			ed.notice = "timeline-start"
+		}
+	case input.ActionScrubForward:
+		if sim.FastForward(timelineScrubStep) == 0 {
+			ed.notice = "timeline-end"
+		}
+	case input.ActionUndo:
+		if !sim.Undo() {
+			ed.notice = "nothing-to-undo"
+		}
+	case input.ActionRedo:
+		if !sim.Redo() {
+			ed.notice = "nothing-to-redo"
+		}
+	case input.ActionFaster:
+		speed.Faster()
+	case input.ActionSlower:
+		speed.Slower()
+	case input.ActionStepLarger:
+		speed.LargerStep()
+	case input.ActionStepSmaller:
+		speed.SmallerStep()
+	case input.ActionMaxSpeed:
+		speed.ToggleMaxSpeed()
+	case input.ActionCopySoupID:
+		if id := sim.SoupID(); id != "" {
+			writeOSC52(ed.osc52, id)
+			ed.notice = "copied:" + id
+		} else {
+			ed.notice = "no-soup-id"
+		}
+	case input.ActionQuit:
+		return true
+	}
+	return false
+}
+
+func mapKeyEvent(ev *tcell.EventKey) string {
+	if ev.Key() == tcell.KeyRune {
+		return runeKeyName(ev.Rune())
+	}
+	if name, ok := specialKeyNames[ev.Key()]; ok {
+		return name
+	}
+	if ev.Key() >= tcell.KeyCtrlA && ev.Key() <= tcell.KeyCtrlZ {
+		return fmt.Sprintf("ctrl-%c", 'a'+rune(ev.Key()-tcell.KeyCtrlA))
+	}
+	return ""
+}
+
+func mapKey(ch byte) string {
+	switch {
+	case ch == '\r' || ch == '\n':
+		return "enter"
+	case ch == '\t':
+		return "tab"
+	case ch == 0x1b:
+		return "esc"
+	case ch == 0x7f || ch == 0x08:
+		return "backspace"
+	case ch == 0:
+		return "ctrl-space"
+	case ch == 0x1f:
+		return "ctrl-_"
+	case ch >= 1 && ch <= 26:
+		return fmt.Sprintf("ctrl-%c", 'a'+ch-1)
+	case ch < 0x20 || ch > 0x7e:
+		return ""
+	}
+	return runeKeyName(rune(ch))
+}
+
+func runeKeyName(r rune) string {
+	if r == ' ' {
+		return "space"
+	}
+	return string(r)
+}
+
+func loadKeymap(path, preset string) (*input.Keymap, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath(keymapFileName)
+	}
+
+	keymap := input.DefaultKeymap()
+	if preset != "" {
+		if err := keymap.ApplyPreset(preset); err != nil {
+			return nil, err
+		}
+	}
+	if path != "" {
+		file, err := os.Open(path)
+		switch {
+		case err == nil:
+			loadErr := keymap.Load(file)
+			file.Close()
+			if loadErr != nil {
+				return nil, fmt.Errorf("%s: %v", path, loadErr)
+			}
+		case explicit || !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+	return keymap, nil
+}
+
+func defaultConfigPath(name string) string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gol-on-cli", name)
+}
@@ -4,14 +4,20 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"os/signal"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
+	"gol-on-cli/internal/analysis"
 	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/cast"
 	"gol-on-cli/internal/cli"
 	"gol-on-cli/internal/engine"
 	"gol-on-cli/internal/input"
@@ -26,48 +32,172 @@ const startupPatternTimeout = 5 * time.Second
 const startupPatternMaxSize int64 = 1024 * 1024
 const frameMarginCols = 6
 const frameMarginRows = 4
-
-type noopLoader struct{}
-
-func (n noopLoader) Load(url string) error { return nil }
+const timelineScrubStep = 10
+const boardTopology = "torus"
 
 func main() {
 	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
 
+var commands = []cli.Command{
+	{Name: "run", Args: "[options]", Summary: "Run the simulation in the terminal, or stream frames with --headless", Run: runSimulation},
+	{Name: "export", Args: "--out <file.gif|file.png> [options]", Summary: "Render a range of generations to an animated GIF or APNG", Run: runExport},
+	{Name: "snapshot", Args: "--out <file.png|file.svg> [options]", Summary: "Render one generation to a PNG or SVG image", Run: runSnapshot},
+	{Name: "replay", Args: "[--speed n] <session.cast>", Summary: "Play back a recorded asciicast session", Run: runReplay},
+	{Name: "convert", Args: "[options] [in] [out]", Summary: "Convert a pattern between RLE, PlainText (.cells) and Life 1.06, cropped and optionally transformed", Run: runConvert},
+	{Name: "analyze", Args: "[options] <pattern|url|->", Summary: "Classify a pattern on an unbounded plane: period, speed, stabilization, heat", Run: runAnalyze},
+	{Name: "search", Args: "[options]", Summary: "Run seeded soups in parallel, take a census of each and keep rare objects and methuselahs", Run: runSearch},
+	{Name: "bench", Args: "[options]", Summary: "Time the board and universe engines on random soups, as text, JSON or benchstat lines", Run: runBench},
+	{Name: "config", Args: "print [options]", Summary: "Print the configuration merged from file, environment and run options", Run: runConfig},
+}
+
 func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
-	flags := flag.NewFlagSet("gol-on-cli", flag.ContinueOnError)
-	flags.SetOutput(stderr)
-
-	help := flags.Bool("help", false, "show usage")
-	showVersion := flags.Bool("version", false, "show version")
-	fps := flags.Int("fps", 5, "updates per second")
-	seed := flags.Int64("seed", 0, "random seed")
-	patternURL := flags.String("pattern-url", "", "startup pattern URL")
+	dispatcher := cli.Dispatcher{Program: "gol-on-cli", Default: "run", Commands: commands}
+	return dispatcher.Run(args, stdin, stdout, stderr)
+}
+
+type runFlags struct {
+	help          *bool
+	showVersion   *bool
+	configPath    *string
+	soupID        *string
+	patternURL    *string
+	headless      *bool
+	generations   *int
+	every         *int
+	ansi          *bool
+	cursorHome    *bool
+	headlessStats *bool
+	width         *int
+	height        *int
+	record        *string
+	keymapPath    *string
+	keysPreset    *string
+}
+
+func defineRunFlags(flags *flag.FlagSet) *runFlags {
+	f := &runFlags{}
+	f.help = flags.Bool("help", false, "show usage")
+	f.showVersion = flags.Bool("version", false, "show version")
+	f.configPath = flags.String("config", "", "config file (default $XDG_CONFIG_HOME/gol-on-cli/config.toml)")
+	flags.Int("fps", 5, "updates per second")
+	flags.Int64("seed", 0, "random seed")
+	flags.String("seed-string", "", "string seed hashed with SHA-256, e.g. k_abc123 (overrides --seed)")
+	flags.String("color", "truecolor", "color mode: truecolor (when the terminal supports it) or 256")
+	f.soupID = flags.String("soup", "", "start at an exact soup ID such as k_abc123#7")
+	f.patternURL = flags.String("pattern-url", "", "startup pattern URL")
 	flags.String("alive-color", "", "alive cell color")
 	flags.String("dead-color", "", "dead cell color")
+	flags.Int("trail", 0, "generations a dead cell keeps fading")
+	f.headless = flags.Bool("headless", false, "stream frames to stdout instead of the TUI")
+	f.generations = flags.Int("generations", 0, "generations to stream in headless mode")
+	f.every = flags.Int("every", 1, "emit a frame every n generations in headless mode")
+	f.ansi = flags.Bool("ansi", false, "emit colored ANSI frames in headless mode")
+	f.cursorHome = flags.Bool("cursor-home", false, "prefix headless frames with a cursor-home escape")
+	f.headlessStats = flags.Bool("stats", false, "append a statistics line to each headless frame")
+	f.width = flags.Int("width", 20, "board width in headless mode")
+	f.height = flags.Int("height", 10, "board height in headless mode")
+	f.record = flags.String("record", "", "record the session to an asciicast v2 file")
+	f.keymapPath = flags.String("keymap", "", "key binding file (default $XDG_CONFIG_HOME/gol-on-cli/keys.conf)")
+	f.keysPreset = flags.String("keys-preset", "", "key binding preset: default, vim or emacs")
+	flags.Float64("soup-density", 0.5, "fraction of live cells in random soups (0-1)")
+	flags.String("soup-size", "20x20", "soup window size: WxH, N or full")
+	flags.String("soup-placement", "center", "soup window placement: center or random")
+	flags.String("soup-symmetry", "C1", "Catagolue-style soup symmetry, e.g. C1, C2_4, C4_1, D2_+1, D8_1")
+	flags.String("restart", "cycle", "auto-restart conditions: still, cycle, population, generations or never")
+	flags.Int("restart-linger", app.DefaultRestartLinger, "generations a still life or cycle lingers before restarting")
+	flags.Int("restart-population", 0, "restart when the population drops below n")
+	flags.Int("restart-generations", 0, "restart after n generations")
+	flags.Duration("restart-fade", 400*time.Millisecond, "fade between soups on auto-restart (0 disables)")
+	flags.String("census-neighborhood", "moore", "how cells group into objects for the restart census: moore or vonneumann, optionally :range")
+	return f
+}
 
-	if err := flags.Parse(args); err != nil {
-		return 1
+func runSimulation(ctx *cli.Context) int {
+	stdin, stdout, stderr := ctx.Stdin, ctx.Stdout, ctx.Stderr
+	flags := ctx.Flags
+	f := defineRunFlags(flags)
+	if code, ok := ctx.Parse(); !ok {
+		return code
 	}
-
-	if *help {
-		fmt.Fprintln(stdout, cli.BuildHelpText())
-		return 0
+	if flags.NArg() > 0 {
+		return ctx.UsageError("unexpected argument %q", flags.Arg(0))
 	}
-	if *showVersion {
+
+	if *f.showVersion && !*f.help {
 		fmt.Fprintln(stdout, cli.BuildVersionText(version))
 		return 0
 	}
 
-	if _, err := cli.Start(cli.StartOptions{PatternURL: *patternURL, FPS: *fps}, noopLoader{}); err != nil {
+	keymap, err := loadKeymap(*f.keymapPath, *f.keysPreset)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to start: invalid keymap: %v\n", err)
+		return 1
+	}
+	if *f.help {
+		fmt.Fprintln(stdout, cli.BuildHelpTextWithKeymap(keymap))
+		return 0
+	}
+	config, _, err := resolveConfig(*f.configPath, flags, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to start: invalid config: %v\n", err)
+		return 1
+	}
+	restartPolicy, err := config.Restart.Policy()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to start: invalid restart policy: %v\n", err)
+		return 1
+	}
+	firstSoup, err := startSoupID(config.Seed, *f.soupID)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to start: %v\n", err)
+		return 1
+	}
+	trueColor := config.ColorMode == app.ColorModeTrueColor && supportsTrueColor()
+	neighborhood, _ := analysis.ParseNeighborhood(config.Census.Neighborhood)
+	if err := cli.ValidateStartOptions(cli.StartOptions{PatternURL: *f.patternURL, FPS: config.FPS}); err != nil {
 		fmt.Fprintf(stderr, "failed to start: %v\n", err)
 		return 1
 	}
 
-	source := patternSource(*patternURL)
+	source := patternSource(*f.patternURL)
+	if *f.headless || (!isTerminal(stdout) && *f.generations > 0) {
+		if *f.generations < 0 {
+			fmt.Fprintln(stderr, "failed to start: invalid generations: must be zero or greater")
+			return 1
+		}
+		if *f.width <= 0 || *f.height <= 0 {
+			fmt.Fprintln(stderr, "failed to start: invalid board size: width and height must be greater than zero")
+			return 1
+		}
+		sim, err := newRunSimulation(*f.width, *f.height, firstSoup, config, restartPolicy)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to start: %v\n", err)
+			return 1
+		}
+		if *f.patternURL != "" {
+			if err := tryLoadPatternForSimulation(sim, *f.patternURL); err != nil {
+				fmt.Fprintf(stderr, "failed to load startup pattern: %v\n", err)
+			}
+		}
+		err = runHeadless(stdout, sim, headlessOptions{
+			generations: *f.generations,
+			every:       *f.every,
+			ansi:        *f.ansi,
+			cursorHome:  *f.cursorHome,
+			stats:       *f.headlessStats,
+			trueColor:   trueColor,
+			trail:       config.Trail,
+			source:      source,
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to run headless: %v\n", err)
+			return 1
+		}
+		return 0
+	}
 	if !isTerminal(stdout) {
-		sim := app.NewSimulation(20, 10, *seed)
+		sim := app.NewSimulationFromSoupID(20, 10, firstSoup, config.Soup)
 		status := renderer.BuildStatusBar(renderer.StatusBarData{Generation: sim.Generation(), Paused: false, PatternSource: source})
 		fmt.Fprintln(stdout, status)
 		return 0
@@ -91,33 +221,108 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	}
 	defer screen.Fini()
 
+	var recorder *cast.Writer
+	if *f.record != "" {
+		writer, closeRecorder, err := openRecorder(*f.record, screen)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to start: %v\n", err)
+			return 1
+		}
+		defer closeRecorder()
+		recorder = writer
+	}
+
 	w, h := boardSizeForScreen(screen)
-	sim := app.NewSimulation(w, h, *seed)
-	if *patternURL != "" {
-		if err := tryLoadPatternForSimulation(sim, *patternURL); err != nil {
+	sim, err := newRunSimulation(w, h, firstSoup, config, restartPolicy)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to start: %v\n", err)
+		return 1
+	}
+	if *f.patternURL != "" {
+		if err := tryLoadPatternForSimulation(sim, *f.patternURL); err != nil {
 			fmt.Fprintf(stderr, "failed to load startup pattern: %v\n", err)
 		}
 	}
 	_ = fileIn
-	return runFullscreen(screen, sim, *fps, source, *patternURL)
+	return runFullscreen(screen, sim, fullscreenOptions{
+		fps:          config.FPS,
+		source:       source,
+		patternURL:   *f.patternURL,
+		trail:        config.Trail,
+		trueColor:    trueColor,
+		recorder:     recorder,
+		clipboard:    os.Stdout,
+		keymap:       keymap,
+		fade:         config.Restart.Fade,
+		neighborhood: neighborhood,
+	})
 }
 
-func runFullscreen(screen tcell.Screen, sim *app.Simulation, fps int, source string, patternURL string) int {
-	ticker := time.NewTicker(time.Second / time.Duration(fps))
+type fullscreenOptions struct {
+	fps          int
+	source       string
+	patternURL   string
+	trail        int
+	recorder     *cast.Writer
+	clipboard    io.Writer
+	keymap       *input.Keymap
+	fade         time.Duration
+	trueColor    bool
+	neighborhood analysis.Neighborhood
+}
+
+func newRunSimulation(width, height int, firstSoup string, config app.Config, policy app.RestartPolicy) (*app.Simulation, error) {
+	sim := app.NewSimulationFromSoupID(width, height, firstSoup, config.Soup)
+	sim.SetRestartPolicy(policy)
+	if sim.RestartsAtOnce() {
+		return nil, fmt.Errorf("invalid restart policy: %s already holds for the first soup %s", policy, sim.SoupID())
+	}
+	return sim, nil
+}
+
+func runFullscreen(screen tcell.Screen, sim *app.Simulation, options fullscreenOptions) int {
+	speed := newSpeedControl(options.fps)
+	interval := speed.Interval()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	env := &commandEnv{speed: speed, source: options.source, patternURL: options.patternURL, neighborhood: options.neighborhood}
+	var meter rateMeter
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	defer signal.Stop(sigCh)
 
-	state := input.NewState()
-	palette := renderer.SelectPalette(supportsTrueColor())
+	state := input.NewStateWithKeymap(options.keymap)
+	palette := renderer.SelectPalette(options.trueColor)
 
 	var previous *engine.Board
 	needsFullClear := true
 	notice := ""
 	helpVisible := false
+	statsVisible := false
+	collector := app.NewStatsCollector(app.DefaultStatsHistory)
+	replaced := sim.Replaced()
+	observe := func() app.Stats {
+		if sim.Replaced() != replaced {
+			replaced = sim.Replaced()
+			collector.Reset()
+		}
+		return collector.Observe(sim.Board(), sim.Generation())
+	}
+	autoRestarts := sim.AutoRestarts()
+	censusCh := make(chan censusResult, 1)
+	lastCensus := autoRestarts
+	var fade *soupFade
+	editMode := false
+	ed := newEditor(options.clipboard)
+	commands := input.NewCommandLine()
+	var overlay []cellCoord
 	var transient map[cellCoord]struct{}
+	var trail *renderer.Trail
+	var trailUpdates []renderer.TrailPoint
+	if options.trail > 0 {
+		trail = renderer.NewTrail(options.trail, 0, 0)
+	}
 	dirty := true
 
 	eventCh := make(chan tcell.Event, 16)
@@ -132,65 +337,225 @@ func runFullscreen(screen tcell.Screen, sim *app.Simulation, fps int, source str
 		}
 	}()
 
+	recorder := options.recorder
+	show := func() {
+		if err := showScreen(screen, recorder); err != nil {
+			recorder = nil
+			notice = fmt.Sprintf("record-failed: %v", err)
+		}
+	}
+
+	advance := func(step func()) bool {
+		generation := sim.Generation()
+		step()
+		if sim.Generation() == generation {
+			return false
+		}
+		if trail != nil {
+			trailUpdates = append(trailUpdates, trail.Advance(sim.Board())...)
+		}
+		observe()
+		return true
+	}
+
 	fitSimulationToScreen(screen, sim)
+	if trail != nil {
+		trail.Seed(sim.Board())
+	}
 	for {
+		if speed.Interval() != interval {
+			interval = speed.Interval()
+			ticker.Reset(interval)
+			meter.Reset()
+		}
+
 		if state.HelpVisible != helpVisible {
 			helpVisible = state.HelpVisible
 			needsFullClear = true
 			dirty = true
 		}
 
+		if state.StatsVisible != statsVisible {
+			statsVisible = state.StatsVisible
+			needsFullClear = true
+			dirty = true
+		}
+
+		if state.EditMode != editMode {
+			editMode = state.EditMode
+			if editMode {
+				screen.EnableMouse()
+			} else {
+				screen.DisableMouse()
+				screen.HideCursor()
+			}
+			dirty = true
+		}
+
+		if state.ConsumeStepRequest() {
+			advance(func() {
+				if sim.FastForward(1) == 0 {
+					sim.Step()
+				}
+			})
+			dirty = true
+		}
+
 		if state.ConsumeLoadPatternRequest() {
-			if patternURL == "" {
+			if env.patternURL == "" {
 				notice = "no-pattern-url-configured"
-			} else if err := tryLoadPatternForSimulation(sim, patternURL); err != nil {
+			} else if err := loadPatternArgument(sim, env.patternURL); err != nil {
 				notice = fmt.Sprintf("pattern-load-failed: %v", err)
 			} else {
 				notice = "pattern-loaded"
 				previous = nil
+				if trail != nil {
+					trail.Seed(sim.Board())
+				}
 			}
 			needsFullClear = true
 			dirty = true
 		}
 
+		if state.ConsumeSnapshotRequest() {
+			path := snapshotFileName(sim.Generation(), time.Now())
+			imageOptions := renderer.ImageOptions{CellSize: snapshotCellSize, Palette: renderer.SelectPalette(true)}
+			if err := writeSnapshot(path, "png", sim.Board(), previous, imageOptions); err != nil {
+				notice = fmt.Sprintf("snapshot-failed: %v", err)
+			} else {
+				notice = "snapshot-saved:" + path
+			}
+			dirty = true
+		}
+
 		if dirty {
 			current := sim.Board()
-			frameNotice := notice
-			if state.HelpVisible {
-				if frameNotice != "" {
-					frameNotice += " | "
-				}
-				frameNotice += "help:q h/? space r l"
+			if ed.notice != "" {
+				notice = ed.notice
+				ed.notice = ""
+			}
+			timelineIndex, timelineSize := sim.TimelinePosition()
+			screenWidth, _ := screen.Size()
+			stats := observe()
+			cycleStatus := ""
+			if cycle, ok := sim.Cycle(); ok {
+				cycleStatus = cycle.String()
 			}
-			status := renderer.BuildStatusBar(renderer.StatusBarData{
+			status := renderer.FitStatusBar(renderer.StatusBarData{
 				Generation:    sim.Generation(),
 				Paused:        state.Paused,
-				PatternSource: source,
-				Notice:        frameNotice,
-			})
-			if needsFullClear {
+				PatternSource: env.source,
+				Notice:        notice,
+				KeyHint:       options.keymap.Hint(),
+				EditMode:      editStatus(state, ed),
+				TimelineIndex: timelineIndex,
+				TimelineSize:  timelineSize,
+				GensPerSecond: meter.Rate(),
+				StepSize:      speed.stepSize,
+				MaxSpeed:      speed.maxSpeed,
+				Summary: &renderer.BoardSummary{
+					Population: stats.Population,
+					BoxX:       stats.BoxX,
+					BoxY:       stats.BoxY,
+					BoxWidth:   stats.BoxWidth,
+					BoxHeight:  stats.BoxHeight,
+				},
+				Rule:        sim.Rule().String(),
+				Topology:    boardTopology,
+				FPS:         speed.fps,
+				PatternName: patternName(env.source),
+				Cycle:       cycleStatus,
+				SoupID:      sim.SoupID(),
+			}, screenWidth)
+			if commands.Active {
+				status = renderer.TruncateColumns(commandStatus(commands), screenWidth)
+			}
+			if fade != nil {
 				screen.Clear()
-				renderBoardFull(screen, current, previous, palette)
+				renderFadeFrame(screen, fade, palette)
 				renderStatusBar(screen, current.Height(), status)
-				screen.Show()
+				show()
+				needsFullClear = true
+			} else if needsFullClear {
+				screen.Clear()
+				renderBoardFull(screen, current, previous, palette, trail)
+				overlay = renderEditOverlay(screen, state, ed, current, previous, palette, trail)
+				renderStatusBar(screen, current.Height(), status)
+				renderStatsPanel(screen, state, collector, current.Height())
+				renderHelpOverlay(screen, state, options.keymap)
+				renderCommandCursor(screen, commands, state, current.Height())
+				show()
 				needsFullClear = false
 				transient = nil
 			} else {
 				updates, nextTransient := diffCells(current, previous, transient)
-				renderCellUpdates(screen, updates, current, previous, palette)
+				renderCellUpdates(screen, updates, current, previous, palette, trail)
+				renderTrailUpdates(screen, trailUpdates, current, previous, palette, trail)
+				for _, coord := range overlay {
+					renderCell(screen, coord.x, coord.y, current, previous, palette, trail)
+				}
 				transient = nextTransient
+				overlay = renderEditOverlay(screen, state, ed, current, previous, palette, trail)
 				renderStatusBar(screen, current.Height(), status)
-				screen.Show()
+				renderStatsPanel(screen, state, collector, current.Height())
+				renderHelpOverlay(screen, state, options.keymap)
+				renderCommandCursor(screen, commands, state, current.Height())
+				show()
 			}
-			previousSnapshot := current
+			previousSnapshot := current.Clone()
 			previous = &previousSnapshot
+			trailUpdates = nil
 			dirty = false
 		}
 
 		select {
-		case <-ticker.C:
-			sim.Tick()
+		case now := <-ticker.C:
+			if fade != nil {
+				fade.step++
+				if fade.step >= fade.steps {
+					fade = nil
+				}
+				dirty = true
+				break
+			}
+			generations := 0
+			if speed.maxSpeed {
+				deadline := now.Add(speed.Budget())
+				for !sim.Paused() && time.Now().Before(deadline) {
+					if advance(sim.Tick) {
+						generations++
+					}
+				}
+			} else {
+				for i := 0; i < speed.stepSize; i++ {
+					if advance(sim.Tick) {
+						generations++
+					}
+				}
+			}
+			if generations > 1 && trail != nil {
+				trailUpdates = nil
+				needsFullClear = true
+			}
+			meter.Add(generations, time.Now())
+			if sim.AutoRestarts() != autoRestarts {
+				autoRestarts = sim.AutoRestarts()
+				startCensus(sim, env, censusCh)
+				if options.fade > 0 && previous != nil {
+					fade = newSoupFade(*previous, options.fade, interval)
+				}
+				if trail != nil {
+					trail.Seed(sim.Board())
+				}
+				needsFullClear = true
+			}
 			dirty = true
+		case result := <-censusCh:
+			if result.restart > lastCensus {
+				lastCensus = result.restart
+				notice = recordCensus(env, result.census)
+				dirty = true
+			}
 		case ev := <-eventCh:
 			if ev == nil {
 				return 0
@@ -200,13 +565,40 @@ func runFullscreen(screen tcell.Screen, sim *app.Simulation, fps int, source str
 				screen.Sync()
 				fitSimulationToScreen(screen, sim)
 				previous = nil
+				if trail != nil {
+					trail.Seed(sim.Board())
+				}
 				needsFullClear = true
 				dirty = true
 			case *tcell.EventKey:
-				if handleKeyEvent(state, sim, tev) {
+				if commands.Active {
+					if line, ok := handleCommandKey(commands, tev); ok {
+						var redraw bool
+						notice, redraw = executeCommand(sim, env, line)
+						if redraw {
+							previous = nil
+							collector.Reset()
+							if trail != nil {
+								trail.Seed(sim.Board())
+							}
+							needsFullClear = true
+						}
+					}
+					dirty = true
+					break
+				}
+				if handleKeyEvent(state, sim, ed, speed, tev) {
 					return 0
 				}
+				if state.ConsumeCommandRequest() {
+					commands.Open()
+				}
 				dirty = true
+			case *tcell.EventMouse:
+				if state.EditMode {
+					handleMouseEvent(state, sim, ed, tev)
+					dirty = true
+				}
 			}
 		case <-sigCh:
 			return 0
@@ -214,6 +606,28 @@ func runFullscreen(screen tcell.Screen, sim *app.Simulation, fps int, source str
 	}
 }
 
+// soupFade dims the last frame of a finished soup before the next one.
+type soupFade struct {
+	from  engine.Board
+	step  int
+	steps int
+}
+
+func newSoupFade(from engine.Board, duration, interval time.Duration) *soupFade {
+	return &soupFade{from: from, steps: max(1, int(duration/interval))}
+}
+
+func renderFadeFrame(screen tcell.Screen, fade *soupFade, palette renderer.Palette) {
+	style := tcell.StyleDefault.Foreground(paletteColor(palette, renderer.FadeColor(palette, fade.step, fade.steps)))
+	for y := 0; y < fade.from.Height(); y++ {
+		for x := 0; x < fade.from.Width(); x++ {
+			if fade.from.IsAlive(x, y) {
+				screen.SetContent(x, y, '█', nil, style)
+			}
+		}
+	}
+}
+
 type cellCoord struct {
 	x int
 	y int
@@ -244,29 +658,43 @@ func diffCells(current engine.Board, previous *engine.Board, transient map[cellC
 	return updates, nextTransient
 }
 
-func renderBoardFull(screen tcell.Screen, board engine.Board, previous *engine.Board, palette renderer.Palette) {
+func renderBoardFull(screen tcell.Screen, board engine.Board, previous *engine.Board, palette renderer.Palette, trail *renderer.Trail) {
 	for y := 0; y < board.Height(); y++ {
 		for x := 0; x < board.Width(); x++ {
-			isAlive := board.IsAlive(x, y)
-			wasAlive := previous != nil && previous.IsAlive(x, y)
-			r, style := cellRenderStyle(isAlive, wasAlive, palette)
-			screen.SetContent(x, y, r, nil, style)
+			renderCell(screen, x, y, board, previous, palette, trail)
 		}
 	}
 }
 
-func renderCellUpdates(screen tcell.Screen, updates []cellCoord, current engine.Board, previous *engine.Board, palette renderer.Palette) {
+func renderCellUpdates(screen tcell.Screen, updates []cellCoord, current engine.Board, previous *engine.Board, palette renderer.Palette, trail *renderer.Trail) {
 	if previous == nil || len(updates) == 0 {
 		return
 	}
 	for _, coord := range updates {
-		isAlive := current.IsAlive(coord.x, coord.y)
-		wasAlive := previous.IsAlive(coord.x, coord.y)
-		r, style := cellRenderStyle(isAlive, wasAlive, palette)
-		screen.SetContent(coord.x, coord.y, r, nil, style)
+		renderCell(screen, coord.x, coord.y, current, previous, palette, trail)
 	}
 }
 
+func renderTrailUpdates(screen tcell.Screen, updates []renderer.TrailPoint, current engine.Board, previous *engine.Board, palette renderer.Palette, trail *renderer.Trail) {
+	for _, point := range updates {
+		renderCell(screen, point.X, point.Y, current, previous, palette, trail)
+	}
+}
+
+func renderCell(screen tcell.Screen, x, y int, board engine.Board, previous *engine.Board, palette renderer.Palette, trail *renderer.Trail) {
+	isAlive := board.IsAlive(x, y)
+	if !isAlive && trail != nil {
+		if age := trail.Age(x, y); age > 0 {
+			color := renderer.TrailColor(palette, age, trail.Length())
+			screen.SetContent(x, y, '█', nil, tcell.StyleDefault.Foreground(paletteColor(palette, color)))
+			return
+		}
+	}
+	wasAlive := previous != nil && previous.IsAlive(x, y)
+	r, style := cellRenderStyle(isAlive, wasAlive, palette)
+	screen.SetContent(x, y, r, nil, style)
+}
+
 func renderStatusBar(screen tcell.Screen, row int, status string) {
 	if row < 0 {
 		row = 0
@@ -278,11 +706,98 @@ func renderStatusBar(screen tcell.Screen, row int, status string) {
 	for x := 0; x < width; x++ {
 		screen.SetContent(x, row, ' ', nil, tcell.StyleDefault)
 	}
-	for i, r := range status {
-		if i >= width {
+	x := 0
+	for _, r := range status {
+		if x >= width {
 			break
 		}
-		screen.SetContent(i, row, r, nil, tcell.StyleDefault)
+		screen.SetContent(x, row, r, nil, tcell.StyleDefault)
+		x++
+	}
+}
+
+func renderStatsPanel(screen tcell.Screen, state *input.State, collector *app.StatsCollector, rows int) {
+	if !state.StatsVisible {
+		return
+	}
+	width, _ := screen.Size()
+	left := max(0, width-renderer.StatsPanelWidth)
+	style := tcell.StyleDefault.Reverse(true)
+	stats := collector.Current()
+	data := renderer.StatsPanelData{
+		BoardSummary: renderer.BoardSummary{
+			Population: stats.Population,
+			BoxX:       stats.BoxX,
+			BoxY:       stats.BoxY,
+			BoxWidth:   stats.BoxWidth,
+			BoxHeight:  stats.BoxHeight,
+		},
+		Generation: stats.Generation,
+		Births:     stats.Births,
+		Deaths:     stats.Deaths,
+		Density:    stats.Density,
+	}
+	for y, row := range renderer.BuildStatsPanel(data, collector.History()) {
+		if y >= rows {
+			break
+		}
+		x := left
+		for _, r := range row {
+			screen.SetContent(x, y, r, nil, style)
+			x++
+		}
+	}
+}
+
+func renderHelpOverlay(screen tcell.Screen, state *input.State, keymap *input.Keymap) {
+	if !state.HelpVisible {
+		return
+	}
+	lines := append(keymap.HelpLines(),
+		"",
+		"mouse in edit mode: left paints, right erases, shift+drag selects",
+		"commands: "+strings.Join(input.CommandUsages(), "  "),
+	)
+	width, height := screen.Size()
+	rows, offset := renderer.BuildHelpOverlay(helpOverlayTitle(keymap), lines, width, height-1, state.HelpScroll)
+	state.HelpScroll = offset
+	if len(rows) == 0 {
+		return
+	}
+	left := (width - utf8.RuneCountInString(rows[0])) / 2
+	top := (height - 1 - len(rows)) / 2
+	style := tcell.StyleDefault.Reverse(true)
+	for y, row := range rows {
+		x := left
+		for _, r := range row {
+			screen.SetContent(x, top+y, r, nil, style)
+			x++
+		}
+	}
+}
+
+func helpOverlayTitle(keymap *input.Keymap) string {
+	keys := func(actions ...input.Action) string {
+		var all []string
+		for _, action := range actions {
+			all = append(all, keymap.Keys(action)...)
+		}
+		return strings.Join(all, "/")
+	}
+	return fmt.Sprintf(" Help — %s close, %s scroll ",
+		keys(input.ActionHelp, input.ActionHelpClose),
+		keys(input.ActionHelpUp, input.ActionHelpDown, input.ActionHelpPageUp, input.ActionHelpPageDown))
+}
+
+func renderCommandCursor(screen tcell.Screen, commands *input.CommandLine, state *input.State, row int) {
+	if commands.Active {
+		_, height := screen.Size()
+		if row >= height {
+			row = height - 1
+		}
+		screen.ShowCursor(1+commands.Cursor(), row)
+	} else if !state.EditMode {
+		screen.HideCursor()
 	}
 }
 
@@ -333,72 +848,6 @@ func parseDecimal(value string) (int, error) {
 	return parsed, nil
 }
 
-func handleKeyEvent(state *input.State, sim *app.Simulation, ev *tcell.EventKey) bool {
-	if ev.Key() == tcell.KeyCtrlC {
-		return true
-	}
-
-	key := mapKeyEvent(ev)
-	if key == "" {
-		return false
-	}
-
-	state.HandleKey(key)
-	switch key {
-	case "space":
-		if state.Paused {
-			sim.Pause()
-		} else {
-			sim.Resume()
-		}
-	case "r":
-		sim.Restart()
-	case "q":
-		return true
-	}
-	return false
-}
-
-func mapKeyEvent(ev *tcell.EventKey) string {
-	switch ev.Key() {
-	case tcell.KeyRune:
-		switch ev.Rune() {
-		case ' ':
-			return "space"
-		case 'h', 'H':
-			return "h"
-		case '?':
-			return "?"
-		case 'r', 'R':
-			return "r"
-		case 'l', 'L':
-			return "l"
-		case 'q', 'Q':
-			return "q"
-		}
-	}
-	return ""
-}
-
-func mapKey(ch byte) string {
-	switch ch {
-	case ' ':
-		return "space"
-	case 'h', 'H':
-		return "h"
-	case '?':
-		return "?"
-	case 'r', 'R':
-		return "r"
-	case 'l', 'L':
-		return "l"
-	case 'q', 'Q':
-		return "q"
-	default:
-		return ""
-	}
-}
-
 func fitSimulationToScreen(screen tcell.Screen, sim *app.Simulation) {
 	width, height := boardSizeForScreen(screen)
 	sim.Resize(width, height)
@@ -440,6 +889,21 @@ func supportsTrueColor() bool {
 	return strings.Contains(strings.ToLower(os.Getenv("COLORTERM")), "truecolor")
 }
 
+func patternName(source string) string {
+	if source == "" || source == "random" {
+		return ""
+	}
+	name := filepath.Base(source)
+	if strings.Contains(source, "://") {
+		name = path.Base(source)
+	}
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return strings.ReplaceAll(name, "_", " ")
+}
+
 func patternSource(patternURL string) string {
 	if patternURL == "" {
 		return "random"
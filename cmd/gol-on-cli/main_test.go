@@ -2,10 +2,41 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"image/gif"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/bench"
+	"gol-on-cli/internal/cast"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/input"
+
+	"github.com/gdamore/tcell/v2"
 )
 
+func TestMain(m *testing.M) {
+	for _, entry := range os.Environ() {
+		if name, _, _ := strings.Cut(entry, "="); strings.HasPrefix(name, "GOL_") {
+			os.Unsetenv(name)
+		}
+	}
+	dir, err := os.MkdirTemp("", "gol-on-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestShouldPrintStatusBarOnDefaultRun(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -42,12 +73,16 @@ func TestShouldPrintPatternSourceWhenPatternURLIsProvided(t *testing.T) {
 
 func TestShouldMapShortcutKeysFromRawBytes(t *testing.T) {
 	cases := map[byte]string{
-		' ': "space",
-		'h': "h",
-		'?': "?",
-		'r': "r",
-		'l': "l",
-		'q': "q",
+		' ':  "space",
+		'h':  "h",
+		'?':  "?",
+		'r':  "r",
+		'l':  "l",
+		's':  "s",
+		'n':  "n",
+		'e':  "e",
+		'\r': "enter",
+		'q':  "q",
 	}
 
 	for in, expected := range cases {
@@ -56,3 +91,1038 @@ func TestShouldMapShortcutKeysFromRawBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestShouldStreamFramesForRequestedGenerationsWhenNotATerminal(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--generations", "4", "--every", "2", "--width", "5", "--height", "3"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	for _, gen := range []string{"gen:0", "gen:2", "gen:4"} {
+		if !strings.Contains(stdout.String(), gen) {
+			t.Fatalf("expected %s frame in output, got %q", gen, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "gen:1 ") || strings.Contains(stdout.String(), "\x1b") {
+		t.Fatalf("expected plain frames every 2 generations, got %q", stdout.String())
+	}
+}
+
+func TestShouldColorSkippedFramesAgainstTheGenerationJustBefore(t *testing.T) {
+	sim := app.NewSimulationWithFactory(5, 5, func(width, height int) engine.Board {
+		blinker := engine.NewBoard(width, height)
+		for x := 1; x <= 3; x++ {
+			blinker.SetAlive(x, 2, true)
+		}
+		return blinker
+	})
+	var stdout bytes.Buffer
+
+	if err := runHeadless(&stdout, sim, headlessOptions{generations: 2, every: 2, ansi: true}); err != nil {
+		t.Fatalf("expected headless run to succeed, got %v", err)
+	}
+
+	// two cells of generation 2 were born in the skipped generation 1
+	frames := strings.Split(stdout.String(), "gen:")
+	newborn := "\x1b[38;5;220m"
+	if len(frames) != 3 || strings.Count(frames[1], newborn) != 2 {
+		t.Fatalf("expected two newborn cells in the gen 2 frame, got %q", stdout.String())
+	}
+}
+
+func TestShouldPrefixAnsiFramesWithCursorHomeWhenRequested(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--headless", "--generations", "1", "--ansi", "--cursor-home"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	if strings.Count(stdout.String(), "\x1b[H") != 2 {
+		t.Fatalf("expected two cursor-home prefixed frames, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "\x1b[0m") {
+		t.Fatalf("expected ANSI color sequences in output, got %q", stdout.String())
+	}
+}
+
+func TestShouldAppendStatsLinesToHeadlessFrames(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--headless", "--generations", "2", "--stats", "--width", "6", "--height", "4"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	if strings.Count(stdout.String(), "stats gen=") != 3 {
+		t.Fatalf("expected a stats line per frame, got %q", stdout.String())
+	}
+	for _, field := range []string{"pop=", "births=", "deaths=", "density=", "box="} {
+		if !strings.Contains(stdout.String(), field) {
+			t.Fatalf("expected %s in stats line, got %q", field, stdout.String())
+		}
+	}
+}
+
+func TestShouldExportAnimatedGIFForGenerationRange(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	out := filepath.Join(t.TempDir(), "run.gif")
+
+	exitCode := run([]string{"export", "--out", out, "--from", "2", "--to", "5", "--width", "8", "--height", "6", "--cell-size", "2"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	file, err := os.Open(out)
+	if err != nil {
+		t.Fatalf("expected exported file, got %v", err)
+	}
+	defer file.Close()
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("expected valid gif, got %v", err)
+	}
+	if len(decoded.Image) != 4 {
+		t.Fatalf("expected 4 frames for generations 2..5, got %d", len(decoded.Image))
+	}
+}
+
+func TestShouldRejectExportWithoutOutputFile(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"export"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Fatalf("expected failure exit code, got %d", exitCode)
+	}
+	if !strings.Contains(stderr.String(), "missing output file") {
+		t.Fatalf("expected missing output error, got %q", stderr.String())
+	}
+}
+
+func TestShouldWriteSVGSnapshotOfRequestedGeneration(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	out := filepath.Join(t.TempDir(), "board.svg")
+
+	exitCode := run([]string{"snapshot", "--out", out, "--generation", "3", "--width", "10", "--height", "10"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected snapshot file, got %v", err)
+	}
+	if !strings.HasPrefix(string(content), "<svg") {
+		t.Fatalf("expected svg document, got %q", content)
+	}
+	if !strings.Contains(stdout.String(), "generation 3") {
+		t.Fatalf("expected saved generation in output, got %q", stdout.String())
+	}
+}
+
+func TestShouldWriteSnapshotColorsRegardlessOfTerminal(t *testing.T) {
+	t.Setenv("COLORTERM", "")
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	out := filepath.Join(t.TempDir(), "board.svg")
+
+	exitCode := run([]string{"snapshot", "--out", out, "--width", "10", "--height", "10"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected snapshot file, got %v", err)
+	}
+	if !strings.Contains(string(content), `fill="#1F2937"`) {
+		t.Fatalf("expected the full-color palette, got %q", content)
+	}
+}
+
+func TestShouldReportRecordingWriteFailures(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("expected simulation screen, got %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(200, 50)
+	file, err := os.Create(filepath.Join(t.TempDir(), "session.cast"))
+	if err != nil {
+		t.Fatalf("expected recording file, got %v", err)
+	}
+	recorder, err := cast.NewWriter(file, cast.Header{Width: 200, Height: 50}, nil)
+	if err != nil {
+		t.Fatalf("expected recorder, got %v", err)
+	}
+	file.Close()
+
+	// a frame this size overflows the write buffer, reaching the closed file
+	if err := showScreen(screen, recorder); err == nil {
+		t.Fatalf("expected a failed recording write to be reported")
+	}
+	if err := showScreen(screen, nil); err != nil {
+		t.Fatalf("expected no error without a recorder, got %v", err)
+	}
+}
+
+func TestShouldSerializeScreenContentIntoANSIFrame(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("expected simulation screen, got %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(3, 2)
+	screen.SetContent(1, 0, 'x', nil, tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 0, 0)))
+
+	frame := screenToANSI(screen)
+
+	if !strings.HasPrefix(frame, "\x1b[H") {
+		t.Fatalf("expected cursor-home prefix, got %q", frame)
+	}
+	if !strings.Contains(frame, "\x1b[38;2;255;0;0mx") {
+		t.Fatalf("expected colored cell in frame, got %q", frame)
+	}
+	if strings.Count(frame, "\r\n") != 1 {
+		t.Fatalf("expected one row separator, got %q", frame)
+	}
+}
+
+func TestShouldReplayRecordedCastToStdout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+	content := "{\"version\":2,\"width\":4,\"height\":1}\n[0,\"o\",\"gen\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("expected cast fixture, got %v", err)
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"replay", "--speed", "100", path}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected success exit code, got %d with stderr %q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "gen") {
+		t.Fatalf("expected replayed output, got %q", stdout.String())
+	}
+}
+
+func TestShouldDrawCellsWhileMovingCursorWithPenDown(t *testing.T) {
+	sim := app.NewSimulationWithFactory(4, 4, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	state := input.NewState()
+
+	for _, ev := range []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone),
+	} {
+		handleKeyEvent(state, sim, newEditor(nil), newSpeedControl(5), ev)
+	}
+
+	for x := 0; x < 3; x++ {
+		if !sim.Board().IsAlive(x, 0) {
+			t.Fatalf("expected pen to draw cell (%d,0)", x)
+		}
+	}
+	if sim.Board().IsAlive(3, 0) {
+		t.Fatalf("expected cell beyond cursor path to stay dead")
+	}
+}
+
+func TestShouldPaintAndEraseWithMouseInEditMode(t *testing.T) {
+	sim := app.NewSimulationWithFactory(4, 4, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	state := input.NewState()
+	ed := newEditor(nil)
+	state.HandleKey("e")
+
+	handleMouseEvent(state, sim, ed, tcell.NewEventMouse(2, 1, tcell.Button1, tcell.ModNone))
+	if !sim.Board().IsAlive(2, 1) {
+		t.Fatalf("expected left click to paint cell")
+	}
+	handleMouseEvent(state, sim, ed, tcell.NewEventMouse(2, 1, tcell.Button2, tcell.ModNone))
+	if sim.Board().IsAlive(2, 1) {
+		t.Fatalf("expected right click to erase cell")
+	}
+	if state.CursorX != 2 || state.CursorY != 1 {
+		t.Fatalf("expected cursor to follow mouse, got (%d,%d)", state.CursorX, state.CursorY)
+	}
+}
+
+func TestShouldCopySelectionToClipboardAndEmitOSC52(t *testing.T) {
+	sim := app.NewSimulationWithFactory(4, 4, func(width, height int) engine.Board {
+		board := engine.NewBoard(width, height)
+		board.SetAlive(0, 0, true)
+		board.SetAlive(1, 1, true)
+		return board
+	})
+	state := input.NewState()
+	var osc bytes.Buffer
+	ed := newEditor(&osc)
+
+	for _, key := range []string{"e", "v", "right", "down", "y"} {
+		state.HandleKey(key)
+		applyEdit(state, sim, ed)
+	}
+
+	if !ed.hasClipboard || ed.clipboard.Width() != 2 || ed.clipboard.Population() != 2 {
+		t.Fatalf("expected 2x2 clipboard with 2 cells, got %dx%d", ed.clipboard.Width(), ed.clipboard.Height())
+	}
+	if !strings.HasPrefix(osc.String(), "\x1b]52;c;") {
+		t.Fatalf("expected OSC 52 clipboard sequence, got %q", osc.String())
+	}
+}
+
+func TestShouldPasteRotatedClipboardAtCursor(t *testing.T) {
+	sim := app.NewSimulationWithFactory(5, 5, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	state := input.NewState()
+	ed := newEditor(nil)
+	stamp := engine.NewBoard(3, 1)
+	stamp.SetAlive(0, 0, true)
+	stamp.SetAlive(1, 0, true)
+	stamp.SetAlive(2, 0, true)
+	ed.clipboard = stamp
+	ed.hasClipboard = true
+
+	for _, key := range []string{"e", "right", "p", "o", "enter"} {
+		state.HandleKey(key)
+		applyEdit(state, sim, ed)
+	}
+
+	for y := 0; y < 3; y++ {
+		if !sim.Board().IsAlive(1, y) {
+			t.Fatalf("expected vertical line at x=1 after rotated paste, missing y=%d", y)
+		}
+	}
+	if sim.Board().Population() != 3 {
+		t.Fatalf("expected 3 pasted cells, got %d", sim.Board().Population())
+	}
+}
+
+func TestShouldUndoPenStrokeAsSingleStepWithUKey(t *testing.T) {
+	sim := app.NewSimulationWithFactory(4, 4, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	state := input.NewState()
+	ed := newEditor(nil)
+
+	for _, ev := range []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'd', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'u', tcell.ModNone),
+	} {
+		handleKeyEvent(state, sim, ed, newSpeedControl(5), ev)
+	}
+
+	if sim.Board().Population() != 0 {
+		t.Fatalf("expected undo to remove whole pen stroke, got %d cells", sim.Board().Population())
+	}
+
+	handleKeyEvent(state, sim, ed, newSpeedControl(5), tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl))
+	if sim.Board().Population() != 2 {
+		t.Fatalf("expected redo to restore pen stroke, got %d cells", sim.Board().Population())
+	}
+}
+
+func TestShouldPauseAndStepBackWithBKey(t *testing.T) {
+	sim := app.NewSimulation(8, 8, 4)
+	sim.Tick()
+	sim.Tick()
+	state := input.NewState()
+
+	handleKeyEvent(state, sim, newEditor(nil), newSpeedControl(5), tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModNone))
+
+	if !state.Paused || !sim.Paused() {
+		t.Fatalf("expected stepping back to pause the simulation")
+	}
+	if sim.Generation() != 1 {
+		t.Fatalf("expected generation 1 after stepping back, got %d", sim.Generation())
+	}
+}
+
+func TestShouldLoadKeymapFileAndApplyPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.conf")
+	if err := os.WriteFile(path, []byte("restart = ctrl-n\n"), 0o644); err != nil {
+		t.Fatalf("expected keymap fixture, got %v", err)
+	}
+
+	keymap, err := loadKeymap(path, "vim")
+	if err != nil {
+		t.Fatalf("expected keymap to load, got %v", err)
+	}
+
+	if action, _ := keymap.Lookup(input.ContextGlobal, "ctrl-n"); action != input.ActionRestart {
+		t.Fatalf("expected ctrl-n bound to restart, got %q", action)
+	}
+	if action, _ := keymap.Lookup(input.ContextEdit, "j"); action != input.ActionCursorDown {
+		t.Fatalf("expected vim preset j binding, got %q", action)
+	}
+}
+
+func TestShouldKeepUserBindingsOverKeysPreset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.conf")
+	if err := os.WriteFile(path, []byte("cursor-left = a\n"), 0o644); err != nil {
+		t.Fatalf("expected keymap fixture, got %v", err)
+	}
+
+	keymap, err := loadKeymap(path, "vim")
+	if err != nil {
+		t.Fatalf("expected keymap to load, got %v", err)
+	}
+
+	if action, _ := keymap.Lookup(input.ContextEdit, "a"); action != input.ActionCursorLeft {
+		t.Fatalf("expected the user's a binding to survive the preset, got %q", action)
+	}
+	if _, ok := keymap.Lookup(input.ContextEdit, "h"); ok {
+		t.Fatalf("expected the preset's h binding to be replaced by the user's")
+	}
+}
+
+func TestShouldFailWhenExplicitKeymapIsMissing(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--keymap", filepath.Join(t.TempDir(), "missing.conf")}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "invalid keymap") {
+		t.Fatalf("expected invalid keymap failure, got %d %q", exitCode, stderr.String())
+	}
+}
+
+func TestShouldPrintHelpWithTheResolvedKeymap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.conf")
+	if err := os.WriteFile(path, []byte("quit = Q\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--help", "--keymap", path, "--keys-preset", "emacs"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected help to succeed, got %d %q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"Q ", "ctrl-p/up", ":goto 1000", ":census out.csv|out.json"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected help to contain %q, got %q", want, stdout.String())
+		}
+	}
+}
+
+func TestShouldNameControlAndSpecialKeysGenerically(t *testing.T) {
+	cases := map[*tcell.EventKey]string{
+		tcell.NewEventKey(tcell.KeyCtrlP, 0, tcell.ModCtrl):  "ctrl-p",
+		tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone): "esc",
+		tcell.NewEventKey(tcell.KeyRune, 'Q', tcell.ModNone): "Q",
+		tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone): "space",
+		tcell.NewEventKey(tcell.KeyDelete, 0, tcell.ModNone): "delete",
+	}
+	for ev, expected := range cases {
+		if got := mapKeyEvent(ev); got != expected {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestShouldExecuteCommandLineEntries(t *testing.T) {
+	sim := app.NewSimulation(12, 12, 1)
+	env := &commandEnv{speed: newSpeedControl(10), source: "random"}
+	path := filepath.Join(t.TempDir(), "out.rle")
+
+	if notice, _ := executeCommand(sim, env, "rule b36/s23"); notice != "rule:B36/S23" {
+		t.Fatalf("expected rule notice, got %q", notice)
+	}
+	if notice, _ := executeCommand(sim, env, "fps 30"); env.speed.fps != 30 || notice != "fps:30" {
+		t.Fatalf("expected fps update, got %d %q", env.speed.fps, notice)
+	}
+	if notice, _ := executeCommand(sim, env, "goto 7"); sim.Generation() != 7 {
+		t.Fatalf("expected goto to reach generation 7, got %d %q", sim.Generation(), notice)
+	}
+	saved := sim.Board()
+	if notice, _ := executeCommand(sim, env, "save "+path); notice != "saved:"+path {
+		t.Fatalf("expected save notice, got %q", notice)
+	}
+	executeCommand(sim, env, "seed 99")
+	if notice, redraw := executeCommand(sim, env, "load "+path); !redraw || env.source != path {
+		t.Fatalf("expected load from file, got %q", notice)
+	}
+	loaded := sim.Board()
+	for y := 0; y < saved.Height(); y++ {
+		for x := 0; x < saved.Width(); x++ {
+			if saved.IsAlive(x, y) != loaded.IsAlive(x, y) {
+				t.Fatalf("expected saved pattern to load back at %d,%d", x, y)
+			}
+		}
+	}
+	if notice, _ := executeCommand(sim, env, "warp 9"); !strings.HasPrefix(notice, "unknown-command") {
+		t.Fatalf("expected unknown command notice, got %q", notice)
+	}
+}
+
+func TestShouldSaveAndLoadTheRuleInRLEHeader(t *testing.T) {
+	sim := app.NewSimulation(10, 10, 3)
+	env := &commandEnv{speed: newSpeedControl(5)}
+	path := filepath.Join(t.TempDir(), "highlife.rle")
+
+	executeCommand(sim, env, "rule B36/S23")
+	if notice, _ := executeCommand(sim, env, "save "+path); notice != "saved:"+path {
+		t.Fatalf("expected save notice, got %q", notice)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(content), "x = 10, y = 10, rule = B36/S23\n") {
+		t.Fatalf("expected the current rule in the RLE header, got %q %v", content, err)
+	}
+
+	executeCommand(sim, env, "rule B3/S23")
+	if notice, _ := executeCommand(sim, env, "load "+path); notice != "loaded:"+path {
+		t.Fatalf("expected load notice, got %q", notice)
+	}
+	if sim.Rule().String() != "B36/S23" {
+		t.Fatalf("expected :load to apply the header rule, got %s", sim.Rule())
+	}
+}
+
+func TestShouldSaveAndLoadPathsWithSpaces(t *testing.T) {
+	sim := app.NewSimulation(10, 10, 3)
+	env := &commandEnv{speed: newSpeedControl(5), patternURL: "https://conwaylife.com/wiki/Glider"}
+	path := filepath.Join(t.TempDir(), "my patterns", "soup one.rle")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("expected fixture directory, got %v", err)
+	}
+
+	if notice, _ := executeCommand(sim, env, "save "+path); notice != "saved:"+path {
+		t.Fatalf("expected save to a path with spaces, got %q", notice)
+	}
+	if notice, redraw := executeCommand(sim, env, "load "+path+"  "); !redraw || notice != "loaded:"+path {
+		t.Fatalf("expected load from a path with spaces, got %q", notice)
+	}
+	if env.patternURL != path {
+		t.Fatalf("expected the load-pattern key to reload %q, got %q", path, env.patternURL)
+	}
+	if notice, _ := executeCommand(sim, env, "fps 30 40"); !strings.HasPrefix(notice, "usage:") {
+		t.Fatalf("expected extra values to be rejected, got %q", notice)
+	}
+}
+
+func TestShouldAdjustSpeedWithKeys(t *testing.T) {
+	state := input.NewState()
+	sim := app.NewSimulation(10, 10, 1)
+	speed := newSpeedControl(10)
+	press := func(r rune) {
+		handleKeyEvent(state, sim, newEditor(nil), speed, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	press('+')
+	press('>')
+	press('>')
+	if speed.fps != 15 || speed.stepSize != 4 {
+		t.Fatalf("expected 15fps x4, got %dfps x%d", speed.fps, speed.stepSize)
+	}
+	press('-')
+	press('-')
+	press('<')
+	if speed.fps != 8 || speed.stepSize != 2 {
+		t.Fatalf("expected 8fps x2, got %dfps x%d", speed.fps, speed.stepSize)
+	}
+	press('w')
+	if !speed.maxSpeed || speed.Interval() != time.Second/maxSpeedRefresh {
+		t.Fatalf("expected max-speed mode to pace redraws at %d fps", maxSpeedRefresh)
+	}
+}
+
+func TestShouldClampStartingFPS(t *testing.T) {
+	if speed := newSpeedControl(1000); speed.fps != maxFPS {
+		t.Fatalf("expected %d fps, got %d", maxFPS, speed.fps)
+	}
+}
+
+func TestShouldMeasureGenerationsPerSecond(t *testing.T) {
+	var meter rateMeter
+	start := time.Unix(0, 0)
+
+	meter.Add(10, start)
+	meter.Add(40, start.Add(250*time.Millisecond))
+	meter.Add(50, start.Add(time.Second))
+
+	if meter.Rate() != 100 {
+		t.Fatalf("expected 100 gen/s, got %v", meter.Rate())
+	}
+}
+
+func TestShouldDerivePatternNameFromSource(t *testing.T) {
+	cases := map[string]string{
+		"https://conwaylife.com/wiki/Gosper_glider_gun": "Gosper glider gun",
+		"patterns/glider.rle":                           "glider",
+		"random":                                        "",
+	}
+	for source, expected := range cases {
+		if got := patternName(source); got != expected {
+			t.Fatalf("expected %q for %q, got %q", expected, source, got)
+		}
+	}
+}
+
+func TestShouldTitleHelpOverlayWithBoundKeys(t *testing.T) {
+	keymap, err := input.ParseKeymap(strings.NewReader("help-close = x\nhelp-down = J\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	title := helpOverlayTitle(keymap)
+
+	if !strings.Contains(title, "h/?/x close") || !strings.Contains(title, "up/k/J/pgup/pgdn/space scroll") {
+		t.Fatalf("expected title built from the bindings, got %q", title)
+	}
+}
+
+func TestShouldRejectUnknownRestartCondition(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--headless", "--generations", "1", "--restart", "sometimes"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "invalid restart policy") {
+		t.Fatalf("expected restart policy failure, got %d %q", exitCode, stderr.String())
+	}
+}
+
+func TestShouldLetRestartFlagsOverrideConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "gol-on-cli"), 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gol-on-cli", "config.toml"), []byte("[restart]\non = \"never\"\nlinger = 7\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("restart", "cycle", "")
+	flags.Int("restart-linger", 100, "")
+	if err := flags.Parse([]string{"--restart", "still"}); err != nil {
+		t.Fatalf("unexpected flag error: %v", err)
+	}
+	config, _, err := resolveConfig("", flags, noEnv)
+	if err != nil {
+		t.Fatalf("expected config to load, got %v", err)
+	}
+
+	if config.Restart.On != "still" || config.Restart.Linger != 7 {
+		t.Fatalf("expected flag to override only restart conditions, got %+v", config.Restart)
+	}
+}
+
+func TestShouldRejectRestartPopulationAboveTheFirstSoup(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"--headless", "--generations", "1", "--restart", "population", "--restart-population", "1000"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 1 || !strings.Contains(stderr.String(), "invalid restart policy") {
+		t.Fatalf("expected the population threshold to be rejected, got %d %q", exitCode, stderr.String())
+	}
+}
+
+func TestShouldFadeForConfiguredDuration(t *testing.T) {
+	fade := newSoupFade(engine.NewBoard(2, 2), 400*time.Millisecond, 100*time.Millisecond)
+
+	if fade.steps != 4 {
+		t.Fatalf("expected four fade steps, got %d", fade.steps)
+	}
+}
+
+func TestShouldRejectInvalidSoupOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"--soup-density", "2"},
+		{"--soup-size", "10y"},
+		{"--soup-placement", "corner"},
+		{"--soup-symmetry", "C3"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		exitCode := run(append([]string{"--headless", "--generations", "1"}, args...), strings.NewReader(""), &stdout, &stderr)
+		if exitCode != 1 || !strings.Contains(stderr.String(), "soup") {
+			t.Fatalf("expected %v to be rejected, got %d %q", args, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestShouldCopySoupIDToClipboard(t *testing.T) {
+	sim := app.NewSimulationFromSoupID(20, 20, "k_abc123#3", app.DefaultSoupSpec())
+	state := input.NewState()
+	var osc bytes.Buffer
+	ed := newEditor(&osc)
+
+	handleKeyEvent(state, sim, ed, newSpeedControl(5), tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
+
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("k_abc123#3")) + "\x07"
+	if osc.String() != want || ed.notice != "copied:k_abc123#3" {
+		t.Fatalf("expected soup id on the clipboard, got %q (%s)", osc.String(), ed.notice)
+	}
+}
+
+func TestShouldPickStartSoupFromFlags(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--seed", "42"}, want: "42#0"},
+		{args: []string{"--seed", "42", "--seed-string", "k_abc123"}, want: "k_abc123#0"},
+		{args: []string{"--seed-string", "k_abc123", "--soup", "k_xyz#5"}, want: "k_xyz#5"},
+	}
+	for _, c := range cases {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Int64("seed", 0, "")
+		flags.String("seed-string", "", "")
+		soup := flags.String("soup", "", "")
+		if err := flags.Parse(c.args); err != nil {
+			t.Fatalf("unexpected flag error: %v", err)
+		}
+		config := app.DefaultConfig()
+		if err := applyConfigFlags(flags, &config, map[string]string{}); err != nil {
+			t.Fatalf("unexpected config error: %v", err)
+		}
+		if got, err := startSoupID(config.Seed, *soup); err != nil || got != c.want {
+			t.Fatalf("expected %s, got %s (%v)", c.want, got, err)
+		}
+	}
+	if _, err := startSoupID("0", "k_xyz"); err == nil {
+		t.Fatalf("expected a soup id without an index to be rejected")
+	}
+}
+
+func TestShouldReseedFromStringCommand(t *testing.T) {
+	sim := app.NewSimulation(20, 20, 1)
+	env := &commandEnv{speed: newSpeedControl(5)}
+
+	if notice, redraw := executeCommand(sim, env, "seed k_abc123"); !redraw || notice != "seed:k_abc123" || sim.SoupID() != "k_abc123#0" {
+		t.Fatalf("expected string reseed, got %s (%s)", notice, sim.SoupID())
+	}
+}
+
+func TestShouldTakeCensusOnRestartAndExportIt(t *testing.T) {
+	sim := app.NewSimulationWithFactory(10, 10, func(width, height int) engine.Board {
+		board := engine.NewBoard(width, height)
+		for _, c := range [][2]int{{1, 1}, {2, 1}, {1, 2}, {2, 2}, {6, 6}, {7, 6}, {8, 6}} {
+			board.SetAlive(c[0], c[1], true)
+		}
+		return board
+	})
+	sim.SetRestartPolicy(app.MaxGenerationsPolicy{Limit: 1})
+	env := &commandEnv{speed: newSpeedControl(5)}
+
+	results := make(chan censusResult, 1)
+
+	if startCensus(sim, env, results) {
+		t.Fatalf("expected no census before a restart")
+	}
+	sim.Tick()
+	if !startCensus(sim, env, results) {
+		t.Fatalf("expected a census after the restart")
+	}
+	result := <-results
+	if notice := recordCensus(env, result.census); result.restart != 1 || notice != "census:1 blinker, 1 block" {
+		t.Fatalf("expected a census notice, got %d %q", result.restart, notice)
+	}
+
+	path := filepath.Join(t.TempDir(), "census.csv")
+	if notice, _ := executeCommand(sim, env, "census "+path); notice != "census-saved:"+path {
+		t.Fatalf("expected census export, got %q", notice)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "apgcode,name,count\nxp2_7,blinker,1\nxs4_33,block,1\n" {
+		t.Fatalf("unexpected census CSV %q %v", content, err)
+	}
+	if notice, _ := executeCommand(sim, env, "census out.txt"); !strings.HasPrefix(notice, "census-failed:") {
+		t.Fatalf("expected an unknown extension to fail, got %q", notice)
+	}
+}
+
+func noEnv(string) (string, bool) { return "", false }
+
+func TestShouldLayerConfigFileEnvironmentAndFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("fps = 12\ntrail = 3\n\n[soup]\nsymmetry = \"D8_1\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	env := map[string]string{"GOL_FPS": "20", "GOL_SOUP_SIZE": "full"}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Int("fps", 5, "")
+	flags.Int("trail", 0, "")
+	if err := flags.Parse([]string{"--fps", "30"}); err != nil {
+		t.Fatalf("unexpected flag error: %v", err)
+	}
+
+	config, origins, err := resolveConfig(path, flags, func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+	if err != nil {
+		t.Fatalf("expected config to resolve, got %v", err)
+	}
+
+	if config.FPS != 30 || config.Trail != 3 || config.Soup.Symmetry != "D8_1" || config.Soup.Width != 0 {
+		t.Fatalf("unexpected merged config %+v", config)
+	}
+	if origins["fps"] != "--fps" || origins["trail"] != path || origins["soup.size"] != "GOL_SOUP_SIZE" {
+		t.Fatalf("unexpected origins %v", origins)
+	}
+}
+
+func TestShouldNameTheLayerOfAnInvalidSetting(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	_, _, err := resolveConfig(filepath.Join(t.TempDir(), "missing.toml"), flags, noEnv)
+	if err == nil {
+		t.Fatalf("expected an explicit missing config file to fail")
+	}
+
+	config := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(config, []byte("fps = 12\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	_, _, err = resolveConfig(config, flags, func(name string) (string, bool) {
+		return "fast", name == "GOL_FPS"
+	})
+	if err == nil || !strings.Contains(err.Error(), "GOL_FPS") {
+		t.Fatalf("expected the environment variable in the error, got %v", err)
+	}
+	_, _, err = resolveConfig(config, flags, func(name string) (string, bool) {
+		return "0", name == "GOL_FPS"
+	})
+	if err == nil || !strings.Contains(err.Error(), "fps: must be greater than zero") {
+		t.Fatalf("expected a range error, got %v", err)
+	}
+}
+
+func TestShouldPrintEffectiveConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOL_RESTART_LINGER", "40")
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"config", "print", "--fps", "30"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected config print to succeed, got %d %q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"fps = 30 # --fps\n", "seed = \"0\"\n", "[soup]\n", "size = \"20x20\"\n", "linger = 40 # GOL_RESTART_LINGER\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in printed config, got:\n%s", want, stdout.String())
+		}
+	}
+	printed, err := app.ParseConfig(strings.NewReader(stdout.String()))
+	if err != nil || printed.FPS != 30 || printed.Restart.Linger != 40 {
+		t.Fatalf("expected printed config to read back, got %+v (%v)", printed, err)
+	}
+}
+
+func TestShouldRunSimulationByNameOrByDefault(t *testing.T) {
+	for _, args := range [][]string{
+		{"--headless", "--generations", "1"},
+		{"run", "--headless", "--generations", "1"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := run(args, strings.NewReader(""), &stdout, &stderr); exitCode != 0 || !strings.Contains(stdout.String(), "gen:1") {
+			t.Fatalf("expected %v to stream frames, got %d %q", args, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestShouldReportUsageErrorsWithExitCodeTwo(t *testing.T) {
+	for _, args := range [][]string{{"frobnicate"}, {"--no-such-flag"}, {"replay"}, {"config", "show"}} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := run(args, strings.NewReader(""), &stdout, &stderr); exitCode != 2 {
+			t.Fatalf("expected usage exit code for %v, got %d %q", args, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestShouldConvertCellsFileToRLE(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "glider.cells")
+	out := filepath.Join(dir, "glider.rle")
+	if err := os.WriteFile(in, []byte("!Name: Glider\n!Found by Richard K. Guy.\n.....\n..O\n...O\n.OOO\n"), 0o644); err != nil {
+		t.Fatalf("failed to write pattern: %v", err)
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"convert", in, out}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected convert to succeed, got %d %q", exitCode, stderr.String())
+	}
+	written, _ := os.ReadFile(out)
+	want := "#N Glider\n#C Found by Richard K. Guy.\n#CXRLE Pos=1,1\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n"
+	if string(written) != want {
+		t.Fatalf("unexpected RLE %q", written)
+	}
+}
+
+func TestShouldConvertStdinToStdoutWithTransforms(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"convert", "--to", "life106", "--rotate", "90", "--translate", "10,-4"}, strings.NewReader("x = 3, y = 1\n3o!\n"), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected convert to succeed, got %d %q", exitCode, stderr.String())
+	}
+	if stdout.String() != "#Life 1.06\n10 -4\n10 -3\n10 -2\n" {
+		t.Fatalf("expected a vertical blinker at 10,-4, got %q", stdout.String())
+	}
+}
+
+func TestShouldRejectBadConvertOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"convert", "--rotate", "45"},
+		{"convert", "--flip", "d"},
+		{"convert", "--translate", "3"},
+		{"convert", "--to", "mc"},
+		{"convert", "in.rle", "out.png"},
+	} {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if exitCode := run(args, strings.NewReader(""), &stdout, &stderr); exitCode != 1 || !strings.Contains(stderr.String(), "failed to convert") {
+			t.Fatalf("expected %v to fail, got %d %q", args, exitCode, stderr.String())
+		}
+	}
+}
+
+func TestShouldAnalyzePatternFileAsText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lwss.cells")
+	if err := os.WriteFile(path, []byte("!Name: LWSS\n.O..O\nO\nO...O\nOOOO\n"), 0o644); err != nil {
+		t.Fatalf("failed to write pattern: %v", err)
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"analyze", path}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected analyze to succeed, got %d %q", exitCode, stderr.String())
+	}
+	for _, want := range []string{"name: LWSS\n", "class: spaceship\n", "period: 4\n", "speed: c/2 orthogonal\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in report, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestShouldAnalyzeStdinAsJSONUnderPatternRule(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	// a single cell survives forever under B/S012345678
+	exitCode := run([]string{"analyze", "--json", "-"}, strings.NewReader("x = 1, y = 1, rule = B/S012345678\no!\n"), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected analyze to succeed, got %d %q", exitCode, stderr.String())
+	}
+	var report analyzeReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON report, got %v: %s", err, stdout.String())
+	}
+	if report.Class != "still life" || report.Rule != "B/S012345678" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestShouldAnalyzeRLEWithNumericSurvivalBirthRule(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"analyze", "--json", "-"}, strings.NewReader("x = 3, y = 1, rule = 23/3\n3o!\n"), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected analyze to succeed, got %d %q", exitCode, stderr.String())
+	}
+	var report analyzeReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON report, got %v: %s", err, stdout.String())
+	}
+	if report.Class != "oscillator" || report.Rule != "B3/S23" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestShouldRunSoupSearchIntoOutputDirectory(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "results")
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"search", "--seed", "k_t", "--soups", "3", "--workers", "2", "--soup-size", "6", "--out", out}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected search to succeed, got %d %q", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "searching k_t#0 to #2 with 2 workers") || !strings.Contains(stdout.String(), "searched 3 soups") {
+		t.Fatalf("unexpected search output %q", stdout.String())
+	}
+	for _, name := range []string{"census.csv", "finds.csv", "summary.json"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Fatalf("expected %s in the output directory: %v", name, err)
+		}
+	}
+}
+
+func TestShouldRejectInvalidSearchOptions(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	if code := run([]string{"search", "extra"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Fatalf("expected a stray argument to be a usage error, got %d", code)
+	}
+	if code := run([]string{"search", "--soup-size", "full"}, strings.NewReader(""), &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "failed to search") {
+		t.Fatalf("expected a full-board soup size to be rejected, got %d %q", code, stderr.String())
+	}
+}
+
+func TestShouldBenchEnginesAsJSON(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := run([]string{"bench", "--generations", "5", "--sizes", "8,16x4", "--densities", "0.5", "--format", "json"}, strings.NewReader(""), &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected bench to succeed, got %d %q", exitCode, stderr.String())
+	}
+	var report bench.Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON, got %v: %s", err, stdout.String())
+	}
+	if len(report.Results) != 4 || report.Results[0].Generations != 5 || report.Results[3].Engine != bench.EngineUniverse || report.Results[3].Width != 16 {
+		t.Fatalf("unexpected results %+v", report.Results)
+	}
+}
+
+func TestShouldRejectInvalidBenchOptions(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	if code := run([]string{"bench", "--engines", "gpu"}, strings.NewReader(""), &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "unknown engine") {
+		t.Fatalf("expected an unknown engine to fail, got %d %q", code, stderr.String())
+	}
+	if code := run([]string{"bench", "now"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Fatalf("expected a stray argument to be a usage error, got %d", code)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gol-on-cli/internal/cast"
+	"gol-on-cli/internal/cli"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func openRecorder(path string, screen tcell.Screen) (*cast.Writer, func() error, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	width, height := screen.Size()
+	writer, err := cast.NewWriter(file, cast.Header{
+		Width:  width,
+		Height: height,
+		Title:  "gol-on-cli",
+		Env:    map[string]string{"TERM": os.Getenv("TERM")},
+	}, nil)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	closer := func() error {
+		flushErr := writer.Flush()
+		closeErr := file.Close()
+		if flushErr != nil {
+			return flushErr
+		}
+		return closeErr
+	}
+	return writer, closer, nil
+}
+
+func showScreen(screen tcell.Screen, recorder *cast.Writer) error {
+	screen.Show()
+	if recorder == nil {
+		return nil
+	}
+	return recorder.WriteOutput(screenToANSI(screen))
+}
+
+func screenToANSI(screen tcell.Screen) string {
+	width, height := screen.Size()
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	lastStyle := ""
+	for y := 0; y < height; y++ {
+		if y > 0 {
+			b.WriteString("\r\n")
+		}
+		for x := 0; x < width; x++ {
+			mainc, combc, style, _ := screen.GetContent(x, y)
+			sgr := styleToSGR(style)
+			if sgr != lastStyle {
+				b.WriteString("\x1b[0m")
+				b.WriteString(sgr)
+				lastStyle = sgr
+			}
+			if mainc == 0 {
+				mainc = ' '
+			}
+			b.WriteRune(mainc)
+			for _, r := range combc {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteString("\x1b[0m")
+	return b.String()
+}
+
+func styleToSGR(style tcell.Style) string {
+	fg, bg, _ := style.Decompose()
+	var b strings.Builder
+	if fg.Valid() {
+		r, g, bl := fg.RGB()
+		fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", r, g, bl)
+	}
+	if bg.Valid() {
+		r, g, bl := bg.RGB()
+		fmt.Fprintf(&b, "\x1b[48;2;%d;%d;%dm", r, g, bl)
+	}
+	return b.String()
+}
+
+func runReplay(ctx *cli.Context) int {
+	stdout, stderr, flags := ctx.Stdout, ctx.Stderr, ctx.Flags
+	speed := flags.Float64("speed", 1, "playback speed multiplier")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if flags.NArg() != 1 {
+		return ctx.UsageError("expected one session file")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to replay: %v\n", err)
+		return 1
+	}
+	defer file.Close()
+
+	_, events, err := cast.Read(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to replay: %v\n", err)
+		return 1
+	}
+	fmt.Fprint(stdout, "\x1b[2J")
+	if err := cast.Play(stdout, events, *speed, nil); err != nil {
+		fmt.Fprintf(stderr, "failed to replay: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout)
+	return 0
+}
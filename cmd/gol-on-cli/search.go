@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"syscall"
+	"time"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/cli"
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/search"
+)
+
+const searchProgressEvery = 100
+
+// runSearch runs a batch of soups without a screen and keeps what is rare.
+// An interrupt stops handing out soups and still writes the results of the
+// ones that finished.
+func runSearch(ctx *cli.Context) int {
+	stdout, stderr, flags := ctx.Stdout, ctx.Stderr, ctx.Flags
+
+	defaults := search.DefaultOptions()
+	seed := flags.String("seed", "", "seed string; soups are <seed>#<index> (default from the current time)")
+	first := flags.Int("first", 0, "index of the first soup, to resume or split a search")
+	soups := flags.Int("soups", search.DefaultSoups, "number of soups to run")
+	workers := flags.Int("workers", runtime.NumCPU(), "soups run in parallel")
+	out := flags.String("out", "search-results", "directory for census.csv, finds.csv, summary.json and soups/")
+	soupSize := flags.String("soup-size", defaults.Soup.Size(), "soup size: WxH or N")
+	density := flags.Float64("soup-density", defaults.Soup.Density, "fraction of live cells in each soup (0-1)")
+	symmetry := flags.String("soup-symmetry", string(defaults.Soup.Symmetry), "Catagolue-style soup symmetry, e.g. C1, D8_1")
+	ruleText := flags.String("rule", "B3/S23", "rule to run")
+	generations := flags.Int("generations", search.DefaultMaxGenerations, "give up on a soup that has not settled after n generations")
+	methuselah := flags.Int("methuselah", search.DefaultMethuselahAge, "keep soups that take at least n generations to settle")
+	neighborhood := flags.String("neighborhood", analysis.DefaultNeighborhood().String(), "how cells group into objects: moore or vonneumann, optionally :range")
+
+	if code, ok := ctx.Parse(); !ok {
+		return code
+	}
+	if flags.NArg() > 0 {
+		return ctx.UsageError("unexpected argument %q", flags.Arg(0))
+	}
+
+	options, err := validateSearchOptions(defaults, *soupSize, *density, *symmetry, *ruleText, *neighborhood)
+	if err == nil && (*soups <= 0 || *workers <= 0 || *generations <= 0 || *methuselah <= 0) {
+		err = fmt.Errorf("soups, workers, generations and methuselah must be greater than zero")
+	}
+	if err == nil && *first < 0 {
+		err = fmt.Errorf("first must be zero or greater")
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to search: %v\n", err)
+		return 1
+	}
+	options.Seed = *seed
+	if options.Seed == "" {
+		options.Seed = "k_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	options.First, options.Soups, options.Workers = *first, *soups, *workers
+	options.MaxGenerations, options.MethuselahAge = *generations, *methuselah
+	options.Progress = func(done int) {
+		if done%searchProgressEvery == 0 && done < options.Soups {
+			fmt.Fprintf(stderr, "searched %d/%d soups\n", done, options.Soups)
+		}
+	}
+
+	fmt.Fprintf(stdout, "searching %s#%d to #%d with %d workers\n", options.Seed, options.First, options.First+options.Soups-1, options.Workers)
+	interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	started := time.Now()
+	report := search.Run(interrupted, options)
+	elapsed := time.Since(started)
+
+	if err := report.Write(*out); err != nil {
+		fmt.Fprintf(stderr, "failed to search: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "searched %d soups in %s (%.1f soups/s)\n", report.Soups, elapsed.Round(time.Millisecond), float64(report.Soups)/max(elapsed.Seconds(), 1e-9))
+	fmt.Fprintf(stdout, "objects: %s\n", report.Census)
+	for _, find := range report.Finds {
+		fmt.Fprintf(stdout, "%s: %s\n", find.SoupID, find)
+	}
+	fmt.Fprintf(stdout, "results in %s\n", *out)
+	return 0
+}
+
+func validateSearchOptions(options search.Options, soupSize string, density float64, symmetry, ruleText, neighborhood string) (search.Options, error) {
+	var err error
+	if options.Soup.Width, options.Soup.Height, err = app.ParseSoupSize(soupSize); err != nil {
+		return options, err
+	}
+	if options.Soup.Width == 0 {
+		return options, fmt.Errorf("invalid soup size %q: a search needs WxH or N", soupSize)
+	}
+	options.Soup.Density = density
+	if options.Soup.Symmetry, err = app.ParseSymmetry(symmetry); err != nil {
+		return options, err
+	}
+	if err := options.Soup.Validate(); err != nil {
+		return options, err
+	}
+	var rule engine.Rule
+	if rule, err = engine.ParseRule(ruleText); err != nil {
+		return options, err
+	}
+	options.Census.Rule = rule
+	if options.Census.Neighborhood, err = analysis.ParseNeighborhood(neighborhood); err != nil {
+		return options, err
+	}
+	return options, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"time"
+)
+
+const (
+	maxFPS           = 120
+	maxStepSize      = 1024
+	maxSpeedRefresh  = 30
+	maxSpeedBudget   = 0.8
+	rateSampleWindow = 500 * time.Millisecond
+)
+
+var fpsSteps = []int{1, 2, 3, 5, 8, 10, 15, 20, 30, 45, 60, 90, maxFPS}
+
+type speedControl struct {
+	fps      int
+	stepSize int
+	maxSpeed bool
+}
+
+func newSpeedControl(fps int) *speedControl {
+	s := &speedControl{stepSize: 1}
+	s.SetFPS(fps)
+	return s
+}
+
+func (s *speedControl) SetFPS(fps int) {
+	if fps > maxFPS {
+		fps = maxFPS
+	}
+	if fps < 1 {
+		fps = 1
+	}
+	s.fps = fps
+}
+
+func (s *speedControl) Faster() {
+	for _, step := range fpsSteps {
+		if step > s.fps {
+			s.fps = step
+			return
+		}
+	}
+}
+
+func (s *speedControl) Slower() {
+	for i := len(fpsSteps) - 1; i >= 0; i-- {
+		if fpsSteps[i] < s.fps {
+			s.fps = fpsSteps[i]
+			return
+		}
+	}
+}
+
+func (s *speedControl) LargerStep() {
+	if s.stepSize < maxStepSize {
+		s.stepSize *= 2
+	}
+}
+
+func (s *speedControl) SmallerStep() {
+	if s.stepSize > 1 {
+		s.stepSize /= 2
+	}
+}
+
+func (s *speedControl) ToggleMaxSpeed() {
+	s.maxSpeed = !s.maxSpeed
+}
+
+// Interval is the ticker period. In max-speed mode it only paces rendering.
+func (s *speedControl) Interval() time.Duration {
+	if s.maxSpeed {
+		return time.Second / maxSpeedRefresh
+	}
+	return time.Second / time.Duration(s.fps)
+}
+
+func (s *speedControl) Budget() time.Duration {
+	return time.Duration(float64(s.Interval()) * maxSpeedBudget)
+}
+
+type rateMeter struct {
+	start time.Time
+	count int
+	rate  float64
+}
+
+func (m *rateMeter) Add(generations int, now time.Time) {
+	if m.start.IsZero() {
+		m.start = now
+	}
+	m.count += generations
+	if elapsed := now.Sub(m.start); elapsed >= rateSampleWindow {
+		m.rate = float64(m.count) / elapsed.Seconds()
+		m.start = now
+		m.count = 0
+	}
+}
+
+func (m *rateMeter) Reset() {
+	*m = rateMeter{}
+}
+
+func (m *rateMeter) Rate() float64 {
+	return m.rate
+}
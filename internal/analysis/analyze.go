@@ -0,0 +1,354 @@
+// Package analysis runs patterns on an unbounded universe and describes what
+// they turn into.
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+type Class string
+
+const (
+	ClassDies       Class = "dies"
+	ClassStillLife  Class = "still life"
+	ClassOscillator Class = "oscillator"
+	ClassSpaceship  Class = "spaceship"
+	ClassMethuselah Class = "methuselah"
+	ClassStabilizes Class = "stabilizes"
+	ClassGun        Class = "gun"
+	ClassPuffer     Class = "puffer"
+	ClassUnknown    Class = "unknown"
+)
+
+const (
+	DefaultMaxGenerations = 20000
+	DefaultMaxPeriod      = 256
+	DefaultMethuselahAge  = 100
+	// growth and debris are checked for regularity this often, over the
+	// generations since the previous check
+	regularityCheckEvery = 1000
+	gunRegionMargin      = 2
+)
+
+type Options struct {
+	Rule           engine.Rule
+	MaxGenerations int
+	MaxPeriod      int
+	// MethuselahAge is how long a pattern must take to settle to count as a
+	// methuselah rather than a predecessor of what it settles into.
+	MethuselahAge int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Rule:           engine.ConwayRule(),
+		MaxGenerations: DefaultMaxGenerations,
+		MaxPeriod:      DefaultMaxPeriod,
+		MethuselahAge:  DefaultMethuselahAge,
+	}
+}
+
+// Result describes a pattern's fate. Period and displacement are of the
+// final periodic state; for guns and puffers Period is the growth period and
+// for patterns with escaping spaceships it is the population period.
+// Stabilization is the first generation of that state.
+type Result struct {
+	Class           Class   `json:"class"`
+	Period          int     `json:"period"`
+	DX              int     `json:"dx"`
+	DY              int     `json:"dy"`
+	Speed           string  `json:"speed,omitempty"`
+	Stabilization   int     `json:"stabilization"`
+	Generations     int     `json:"generations"`
+	InitialPop      int     `json:"initial_population"`
+	FinalPopulation int     `json:"final_population"`
+	MaxBoxWidth     int     `json:"max_box_width"`
+	MaxBoxHeight    int     `json:"max_box_height"`
+	Heat            float64 `json:"heat"`
+	Temperature     float64 `json:"temperature"`
+	// Final is the universe when the run stopped.
+	Final engine.Universe `json:"-"`
+}
+
+type snapshot struct {
+	generation int
+	x, y       int
+	hash       uint64
+	universe   engine.Universe
+}
+
+// Analyze runs start until it repeats (up to translation), dies, or its
+// population settles into a regular pattern, then classifies it.
+func Analyze(start engine.Universe, options Options) Result {
+	if options.MaxPeriod <= 0 {
+		options.MaxPeriod = DefaultMaxPeriod
+	}
+	if options.MaxGenerations <= 0 {
+		options.MaxGenerations = DefaultMaxGenerations
+	}
+	result := Result{InitialPop: start.Population()}
+	startX, startY, startW, startH, _ := start.Bounds()
+
+	current := start
+	populations := []int{current.Population()}
+	window := make([]snapshot, 0, options.MaxPeriod+1)
+	byHash := map[uint64][]int{}
+
+	for generation := 0; ; generation++ {
+		x, y, w, h, alive := current.Bounds()
+		result.MaxBoxWidth = max(result.MaxBoxWidth, w)
+		result.MaxBoxHeight = max(result.MaxBoxHeight, h)
+		result.Generations = generation
+		result.FinalPopulation = current.Population()
+		result.Final = current
+		if !alive {
+			result.Class = ClassDies
+			result.Stabilization = generation
+			return result
+		}
+
+		snap := snapshot{generation: generation, x: x, y: y, hash: shapeHash(current, x, y), universe: current}
+		for _, earlier := range byHash[snap.hash] {
+			match := window[earlier-window[0].generation]
+			if !sameShape(match, snap) {
+				continue
+			}
+			result.Period = generation - match.generation
+			result.DX, result.DY = snap.x-match.x, snap.y-match.y
+			result.Stabilization = match.generation
+			result.Class = periodicClass(result, options.MethuselahAge)
+			if result.DX != 0 || result.DY != 0 {
+				result.Speed = FormatSpeed(result.DX, result.DY, result.Period)
+			}
+			window = append(window, snap)
+			measureHeat(&result, window[len(window)-1-result.Period:], result.DX != 0 || result.DY != 0)
+			return result
+		}
+		window = append(window, snap)
+		byHash[snap.hash] = append(byHash[snap.hash], generation)
+		if len(window) > options.MaxPeriod {
+			dropped := window[0]
+			window = window[1:]
+			byHash[dropped.hash] = removeGeneration(byHash[dropped.hash], dropped.generation)
+		}
+
+		if generation > 0 && generation%regularityCheckEvery == 0 || generation == options.MaxGenerations {
+			period, growth, since, ok := populationPeriod(populations, options.MaxPeriod, regularityCheckEvery)
+			if ok || generation == options.MaxGenerations {
+				if !ok {
+					result.Class = ClassUnknown
+					measureHeat(&result, window[max(0, len(window)-2):], false)
+					return result
+				}
+				result.Period = period
+				result.Stabilization = since
+				switch {
+				case growth == 0 && since >= options.MethuselahAge:
+					result.Class = ClassMethuselah
+				case growth == 0:
+					result.Class = ClassStabilizes
+				case stationaryEngine(window, period, startX-gunRegionMargin, startY-gunRegionMargin, startW+2*gunRegionMargin, startH+2*gunRegionMargin):
+					result.Class = ClassGun
+				default:
+					result.Class = ClassPuffer
+				}
+				if len(window) > period {
+					measureHeat(&result, window[len(window)-1-period:], false)
+				}
+				return result
+			}
+		}
+
+		current = current.Step(options.Rule)
+		populations = append(populations, current.Population())
+	}
+}
+
+func periodicClass(result Result, methuselahAge int) Class {
+	switch {
+	case result.Stabilization >= methuselahAge:
+		return ClassMethuselah
+	case result.DX != 0 || result.DY != 0:
+		return ClassSpaceship
+	case result.Period == 1:
+		return ClassStillLife
+	}
+	return ClassOscillator
+}
+
+func removeGeneration(generations []int, generation int) []int {
+	for i, g := range generations {
+		if g == generation {
+			return append(generations[:i], generations[i+1:]...)
+		}
+	}
+	return generations
+}
+
+// shapeHash ignores position so translated copies collide. Cell hashes are
+// summed, so the order cells are visited in does not matter.
+func shapeHash(u engine.Universe, x, y int) uint64 {
+	var sum uint64
+	u.Each(func(c engine.Cell) {
+		sum += mix(uint64(uint32(c.X-x))<<32 | uint64(uint32(c.Y-y)))
+	})
+	return sum
+}
+
+// mix is the splitmix64 finalizer.
+func mix(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xbf58476d1ce4e5b9
+	v ^= v >> 27
+	v *= 0x94d049bb133111eb
+	return v ^ v>>31
+}
+
+func sameShape(a, b snapshot) bool {
+	if a.universe.Population() != b.universe.Population() {
+		return false
+	}
+	same := true
+	a.universe.Each(func(c engine.Cell) {
+		same = same && b.universe.IsAlive(c.X-a.x+b.x, c.Y-a.y+b.y)
+	})
+	return same
+}
+
+// populationPeriod finds the smallest period p whose population change
+// pop[t]-pop[t-p] has been the same for at least the last span generations,
+// and the generation that regularity began.
+func populationPeriod(populations []int, maxPeriod, span int) (period, growth, since int, ok bool) {
+	last := len(populations) - 1
+	for p := 1; p <= maxPeriod && 4*p <= span && last-span >= p; p++ {
+		growth = populations[last] - populations[last-p]
+		t := last
+		for t-p >= 0 && populations[t]-populations[t-p] == growth {
+			t--
+		}
+		if last-t >= span {
+			return p, growth, t - p + 1, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// stationaryEngine reports whether the cells in the starting area keep
+// changing with the growth period, as a gun's do, rather than being left as
+// debris the way a puffer's are. Debris of period one or two does not count.
+func stationaryEngine(window []snapshot, period, x, y, width, height int) bool {
+	if len(window) <= period || period <= 2 {
+		return false
+	}
+	last := len(window) - 1
+	region := func(i int) engine.Board { return window[i].universe.Board(x, y, width, height) }
+	now := region(last)
+	return boardsEqual(now, region(last-period)) && !boardsEqual(now, region(last-1)) && !boardsEqual(now, region(last-2))
+}
+
+func boardsEqual(a, b engine.Board) bool {
+	for y := 0; y < a.Height(); y++ {
+		for x := 0; x < a.Width(); x++ {
+			if a.IsAlive(x, y) != b.IsAlive(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// measureHeat sets heat, the average number of cells changing state per
+// generation across phases, and temperature, heat per cell that is alive in
+// any phase. Moving patterns are compared in their own frame.
+func measureHeat(result *Result, phases []snapshot, comoving bool) {
+	if len(phases) < 2 {
+		return
+	}
+	changes := 0
+	occupied := map[engine.Cell]struct{}{}
+	for i, phase := range phases {
+		for _, c := range phase.universe.Cells() {
+			if comoving {
+				c = engine.Cell{X: c.X - phase.x, Y: c.Y - phase.y}
+			}
+			occupied[c] = struct{}{}
+		}
+		if i > 0 {
+			changes += changedCells(phases[i-1].universe, phase.universe)
+		}
+	}
+	result.Heat = float64(changes) / float64(len(phases)-1)
+	if len(occupied) > 0 {
+		result.Temperature = result.Heat / float64(len(occupied))
+	}
+}
+
+func changedCells(before, after engine.Universe) int {
+	changed := 0
+	for _, c := range before.Cells() {
+		if !after.IsAlive(c.X, c.Y) {
+			changed++
+		}
+	}
+	for _, c := range after.Cells() {
+		if !before.IsAlive(c.X, c.Y) {
+			changed++
+		}
+	}
+	return changed
+}
+
+// FormatSpeed writes a displacement per period the usual way: c/4 diagonal,
+// c/2 orthogonal, 2c/5 orthogonal, (2,1)c/6 oblique.
+func FormatSpeed(dx, dy, period int) string {
+	ax, ay := abs(dx), abs(dy)
+	steps := max(ax, ay)
+	divisor := gcd(steps, period)
+	speed := fmt.Sprintf("c/%d", period/divisor)
+	if steps/divisor != 1 {
+		speed = fmt.Sprintf("%dc/%d", steps/divisor, period/divisor)
+	}
+	switch {
+	case ax == 0 || ay == 0:
+		return speed + " orthogonal"
+	case ax == ay:
+		return speed + " diagonal"
+	}
+	g := gcd(gcd(ax, ay), period)
+	return fmt.Sprintf("(%d,%d)c/%d oblique", max(ax, ay)/g, min(ax, ay)/g, period/g)
+}
+
+func (r Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "class: %s\n", r.Class)
+	if r.Period > 0 {
+		fmt.Fprintf(&b, "period: %d\n", r.Period)
+	}
+	if r.Speed != "" {
+		fmt.Fprintf(&b, "displacement: (%d,%d)\nspeed: %s\n", r.DX, r.DY, r.Speed)
+	}
+	fmt.Fprintf(&b, "stabilization: %d\n", r.Stabilization)
+	fmt.Fprintf(&b, "generations run: %d\n", r.Generations)
+	fmt.Fprintf(&b, "population: %d -> %d\n", r.InitialPop, r.FinalPopulation)
+	fmt.Fprintf(&b, "max bounding box: %dx%d\n", r.MaxBoxWidth, r.MaxBoxHeight)
+	fmt.Fprintf(&b, "heat: %.2f\n", r.Heat)
+	fmt.Fprintf(&b, "temperature: %.3f\n", r.Temperature)
+	return b.String()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
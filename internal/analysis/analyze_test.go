@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/pattern"
+)
+
+func universe(t *testing.T, rle string) engine.Universe {
+	t.Helper()
+	p, err := pattern.Decode(pattern.FormatRLE, rle)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", rle, err)
+	}
+	return engine.UniverseFromBoard(p.Board, 0, 0)
+}
+
+func TestShouldClassifyPeriodicObjects(t *testing.T) {
+	cases := []struct {
+		name, rle string
+		class     Class
+		period    int
+		speed     string
+	}{
+		{"block", "x = 2, y = 2\n2o$2o!", ClassStillLife, 1, ""},
+		{"blinker", "x = 3, y = 1\n3o!", ClassOscillator, 2, ""},
+		{"glider", "x = 3, y = 3\nbo$2bo$3o!", ClassSpaceship, 4, "c/4 diagonal"},
+		{"lwss", "x = 5, y = 4\nbo2bo$o4b$o3bo$4o!", ClassSpaceship, 4, "c/2 orthogonal"},
+	}
+	for _, c := range cases {
+		result := Analyze(universe(t, c.rle), DefaultOptions())
+		if result.Class != c.class || result.Period != c.period || result.Speed != c.speed || result.Stabilization != 0 {
+			t.Fatalf("%s: unexpected result %+v", c.name, result)
+		}
+	}
+}
+
+func TestShouldMeasureHeatAndTemperature(t *testing.T) {
+	result := Analyze(universe(t, "x = 3, y = 1\n3o!"), DefaultOptions())
+
+	// four cells change each generation; five cells are alive in some phase
+	if result.Heat != 4 || result.Temperature != 0.8 {
+		t.Fatalf("expected heat 4 and temperature 0.8, got %v %v", result.Heat, result.Temperature)
+	}
+	if result.MaxBoxWidth != 3 || result.MaxBoxHeight != 3 {
+		t.Fatalf("expected a 3x3 maximum box, got %dx%d", result.MaxBoxWidth, result.MaxBoxHeight)
+	}
+}
+
+func TestShouldReportWhenPatternsDieOrSettle(t *testing.T) {
+	diehard := Analyze(universe(t, "x = 8, y = 3\n6bo$2o$bo3b3o!"), DefaultOptions())
+	if diehard.Class != ClassDies || diehard.Stabilization != 130 || diehard.FinalPopulation != 0 {
+		t.Fatalf("expected diehard to die at 130, got %+v", diehard)
+	}
+
+	rpentomino := Analyze(universe(t, "x = 3, y = 3\nb2o$2o$bo!"), DefaultOptions())
+	if rpentomino.Class != ClassMethuselah || rpentomino.Stabilization != 1103 || rpentomino.FinalPopulation != 116 {
+		t.Fatalf("expected R-pentomino to settle at 1103 with 116 cells, got %+v", rpentomino)
+	}
+
+	predecessor := Analyze(universe(t, "x = 2, y = 2\n2o$bo!"), DefaultOptions())
+	if predecessor.Class != ClassStillLife || predecessor.Stabilization != 1 {
+		t.Fatalf("expected the tromino to become a block, got %+v", predecessor)
+	}
+}
+
+func TestShouldRecogniseGliderGun(t *testing.T) {
+	gun := "x = 36, y = 9\n24bo$22bobo$12b2o6b2o12b2o$11bo3bo4b2o12b2o$2o8bo5bo3b2o$2o8bo3bob2o4bobo$10bo5bo7bo$11bo3bo$12b2o!"
+
+	result := Analyze(universe(t, gun), DefaultOptions())
+
+	if result.Class != ClassGun || result.Period != 30 {
+		t.Fatalf("expected a period 30 gun, got %+v", result)
+	}
+}
+
+func TestShouldFormatSpeeds(t *testing.T) {
+	cases := map[[3]int]string{
+		{1, 1, 4}:  "c/4 diagonal",
+		{0, -2, 4}: "c/2 orthogonal",
+		{2, 0, 5}:  "2c/5 orthogonal",
+		{2, 1, 6}:  "(2,1)c/6 oblique",
+	}
+	for in, want := range cases {
+		if got := FormatSpeed(in[0], in[1], in[2]); got != want {
+			t.Fatalf("expected %q for %v, got %q", want, in, got)
+		}
+	}
+}
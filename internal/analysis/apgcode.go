@@ -0,0 +1,189 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+const wechsler = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// objectNames are the objects a census names instead of printing their
+// apgcode. Common ones are the dozen or so that turn up in almost every
+// soup; a search reports everything else as rare.
+var objectNames = map[string]objectName{
+	"xs4_33":         {"block", "blocks", true},
+	"xs6_696":        {"beehive", "beehives", true},
+	"xs7_2596":       {"loaf", "loaves", true},
+	"xs5_253":        {"boat", "boats", true},
+	"xs6_356":        {"ship", "ships", true},
+	"xs4_252":        {"tub", "tubs", true},
+	"xs8_6996":       {"pond", "ponds", true},
+	"xs6_25a4":       {"barge", "barges", true},
+	"xs7_25ac":       {"long boat", "long boats", true},
+	"xs8_69ic":       {"mango", "mangoes", false},
+	"xs7_178c":       {"eater", "eaters", false},
+	"xs6_39c":        {"aircraft carrier", "aircraft carriers", false},
+	"xs6_bd":         {"snake", "snakes", false},
+	"xs9_31ego":      {"integral sign", "integral signs", false},
+	"xs9_4aar":       {"hat", "hats", false},
+	"xs12_g8o653z11": {"ship-tie", "ship-ties", false},
+	"xs8_rr":         {"bi-block", "bi-blocks", false},
+	"xp2_7":          {"blinker", "blinkers", true},
+	"xp2_7e":         {"toad", "toads", true},
+	"xp2_318c":       {"beacon", "beacons", true},
+	"xp15_4r4z4r4":   {"pentadecathlon", "pentadecathlons", false},
+	"xq4_153":        {"glider", "gliders", true},
+	"xq4_6frc":       {"lightweight spaceship", "lightweight spaceships", false},
+	"xq4_27dee6":     {"middleweight spaceship", "middleweight spaceships", false},
+	"xq4_27deee6":    {"heavyweight spaceship", "heavyweight spaceships", false},
+}
+
+type objectName struct {
+	singular string
+	plural   string
+	common   bool
+}
+
+// Apgcode names a periodic object the way Catagolue does: xs<population>
+// for still lifes, xp<period> for oscillators and xq<period> for
+// spaceships, followed by the extended Wechsler code of its canonical
+// phase and orientation. ok is false when the object does not repeat
+// within maxPeriod generations.
+func Apgcode(object engine.Universe, rule engine.Rule, maxPeriod int) (code string, ok bool) {
+	if object.Population() == 0 {
+		return "", false
+	}
+	phases := []engine.Universe{object}
+	x0, y0, _, _, _ := object.Bounds()
+	current := object
+	for generation := 1; generation <= maxPeriod; generation++ {
+		current = current.Step(rule)
+		x, y, _, _, alive := current.Bounds()
+		if !alive {
+			return "", false
+		}
+		if !sameShape(snapshot{x: x0, y: y0, universe: object}, snapshot{x: x, y: y, universe: current}) {
+			phases = append(phases, current)
+			continue
+		}
+		best := ""
+		for _, phase := range phases {
+			for _, orientation := range orientations(phase.Cells()) {
+				candidate := wechslerCode(orientation)
+				if best == "" || len(candidate) < len(best) || len(candidate) == len(best) && candidate < best {
+					best = candidate
+				}
+			}
+		}
+		switch {
+		case x != x0 || y != y0:
+			return fmt.Sprintf("xq%d_%s", generation, best), true
+		case generation == 1:
+			return fmt.Sprintf("xs%d_%s", object.Population(), best), true
+		}
+		return fmt.Sprintf("xp%d_%s", generation, best), true
+	}
+	return "", false
+}
+
+// ObjectName is the common name for an apgcode, or "" for objects without
+// one.
+func ObjectName(code string) string {
+	return objectNames[code].singular
+}
+
+// IsCommonObject reports whether code is one of the objects almost every
+// soup leaves behind.
+func IsCommonObject(code string) bool {
+	return objectNames[code].common
+}
+
+// orientations returns the cells under all eight rotations and reflections,
+// each moved so its bounding box starts at the origin.
+func orientations(cells []engine.Cell) [][]engine.Cell {
+	transforms := []func(c engine.Cell) engine.Cell{
+		func(c engine.Cell) engine.Cell { return c },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: -c.X, Y: c.Y} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: c.X, Y: -c.Y} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: -c.X, Y: -c.Y} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: c.Y, Y: c.X} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: -c.Y, Y: c.X} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: c.Y, Y: -c.X} },
+		func(c engine.Cell) engine.Cell { return engine.Cell{X: -c.Y, Y: -c.X} },
+	}
+	result := make([][]engine.Cell, 0, len(transforms))
+	for _, transform := range transforms {
+		moved := make([]engine.Cell, len(cells))
+		for i, c := range cells {
+			moved[i] = transform(c)
+		}
+		minX, minY := moved[0].X, moved[0].Y
+		for _, c := range moved {
+			minX, minY = min(minX, c.X), min(minY, c.Y)
+		}
+		for i := range moved {
+			moved[i].X -= minX
+			moved[i].Y -= minY
+		}
+		result = append(result, moved)
+	}
+	return result
+}
+
+// wechslerCode encodes cells whose bounding box starts at the origin in
+// extended Wechsler format: strips of five rows, one character per column
+// with the top row as the lowest bit, strips joined by z, and runs of
+// empty columns shortened to w, x or y<n>.
+func wechslerCode(cells []engine.Cell) string {
+	width, height := 0, 0
+	for _, c := range cells {
+		width, height = max(width, c.X+1), max(height, c.Y+1)
+	}
+	strips := make([][]int, (height+4)/5)
+	for i := range strips {
+		strips[i] = make([]int, width)
+	}
+	for _, c := range cells {
+		strips[c.Y/5][c.X] |= 1 << (c.Y % 5)
+	}
+	encoded := make([]string, len(strips))
+	for i, columns := range strips {
+		for len(columns) > 0 && columns[len(columns)-1] == 0 {
+			columns = columns[:len(columns)-1]
+		}
+		var b strings.Builder
+		zeros := 0
+		flush := func() {
+			for zeros > 0 {
+				switch {
+				case zeros >= 4:
+					run := min(zeros, 39)
+					b.WriteByte('y')
+					b.WriteByte(wechsler[run-4])
+					zeros -= run
+				case zeros == 3:
+					b.WriteByte('x')
+					zeros = 0
+				case zeros == 2:
+					b.WriteByte('w')
+					zeros = 0
+				default:
+					b.WriteByte('0')
+					zeros = 0
+				}
+			}
+		}
+		for _, column := range columns {
+			if column == 0 {
+				zeros++
+				continue
+			}
+			flush()
+			b.WriteByte(wechsler[column])
+		}
+		encoded[i] = b.String()
+	}
+	return strings.Join(encoded, "z")
+}
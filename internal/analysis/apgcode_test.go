@@ -0,0 +1,47 @@
+package analysis
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldComputeCatagolueApgcodes(t *testing.T) {
+	cases := []struct{ rle, code string }{
+		{"x = 2, y = 2\n2o$2o!", "xs4_33"},
+		{"x = 4, y = 3\nb2o$o2bo$b2o!", "xs6_696"},
+		{"x = 4, y = 4\nb2o$o2bo$bobo$2bo!", "xs7_2596"},
+		{"x = 3, y = 3\n2o$obo$bo!", "xs5_253"},
+		{"x = 4, y = 4\nb2o$o2bo$o2bo$b2o!", "xs8_6996"},
+		{"x = 3, y = 1\n3o!", "xp2_7"},
+		{"x = 4, y = 2\nb3o$3o!", "xp2_7e"},
+		{"x = 4, y = 4\n2o$o$3bo$2b2o!", "xp2_318c"},
+		{"x = 3, y = 3\nbo$2bo$3o!", "xq4_153"},
+		{"x = 5, y = 4\nbo2bo$o4b$o3bo$4o!", "xq4_6frc"},
+		{"x = 6, y = 5\n3bo2b$bo3bo$o5b$o4bo$5o!", "xq4_27dee6"},
+		{"x = 10, y = 3\n2bo4bo$2ob4ob2o$2bo4bo!", "xp15_4r4z4r4"},
+		{"x = 6, y = 6\n2o$obo$b2o$3b2o$3bobo$4b2o!", "xs12_g8o653z11"},
+		{"x = 14, y = 2\n2o10b2o$2o10b2o!", "xs8_33y633"},
+		{"x = 2, y = 8\n2o$2o5$2o$2o!", "xs8_33z66"},
+	}
+	for _, c := range cases {
+		code, ok := Apgcode(universe(t, c.rle), engine.ConwayRule(), DefaultMaxPeriod)
+		if !ok || code != c.code {
+			t.Fatalf("expected %s for %q, got %q %v", c.code, c.rle, code, ok)
+		}
+	}
+}
+
+func TestShouldNotCodeObjectsThatNeverRepeat(t *testing.T) {
+	if code, ok := Apgcode(universe(t, "x = 3, y = 3\nb2o$2o$bo!"), engine.ConwayRule(), 50); ok {
+		t.Fatalf("expected the R-pentomino not to repeat within 50 generations, got %s", code)
+	}
+}
+
+func TestShouldTellCommonObjectsFromRareOnes(t *testing.T) {
+	for code, common := range map[string]bool{"xs4_33": true, "xq4_153": true, "xs8_69ic": false, "xp15_4r4z4r4": false, "xs14_xxx": false} {
+		if IsCommonObject(code) != common {
+			t.Fatalf("expected IsCommonObject(%s) to be %v", code, common)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+const (
+	NeighborhoodMoore      = "moore"
+	NeighborhoodVonNeumann = "vonneumann"
+	// DefaultCensusMaxPeriod covers every common soup oscillator, up to the
+	// period 15 pentadecathlon, with room to spare.
+	DefaultCensusMaxPeriod = 60
+	// UnidentifiedCode tallies islands that do not repeat on their own.
+	UnidentifiedCode = "unidentified"
+)
+
+// Neighborhood decides which live cells belong to the same island: those
+// within Range steps in the Moore (king move) or von Neumann (rook step)
+// sense.
+type Neighborhood struct {
+	Shape string
+	Range int
+}
+
+func DefaultNeighborhood() Neighborhood {
+	return Neighborhood{Shape: NeighborhoodMoore, Range: 1}
+}
+
+// ParseNeighborhood reads "moore", "vonneumann" or either with a range,
+// such as "moore:2".
+func ParseNeighborhood(text string) (Neighborhood, error) {
+	shape, rangeText, hasRange := strings.Cut(strings.ToLower(strings.TrimSpace(text)), ":")
+	n := Neighborhood{Shape: strings.ReplaceAll(shape, "-", ""), Range: 1}
+	if n.Shape != NeighborhoodMoore && n.Shape != NeighborhoodVonNeumann {
+		return Neighborhood{}, fmt.Errorf("unknown neighborhood %q (available: moore, vonneumann, optionally with :range)", text)
+	}
+	if hasRange {
+		r, err := strconv.Atoi(rangeText)
+		if err != nil || r <= 0 {
+			return Neighborhood{}, fmt.Errorf("invalid neighborhood range %q: must be greater than zero", rangeText)
+		}
+		n.Range = r
+	}
+	return n, nil
+}
+
+func (n Neighborhood) String() string {
+	if n.Range == 1 {
+		return n.Shape
+	}
+	return fmt.Sprintf("%s:%d", n.Shape, n.Range)
+}
+
+func (n Neighborhood) offsets() []engine.Cell {
+	var offsets []engine.Cell
+	for dy := -n.Range; dy <= n.Range; dy++ {
+		for dx := -n.Range; dx <= n.Range; dx++ {
+			if dx == 0 && dy == 0 || n.Shape == NeighborhoodVonNeumann && abs(dx)+abs(dy) > n.Range {
+				continue
+			}
+			offsets = append(offsets, engine.Cell{X: dx, Y: dy})
+		}
+	}
+	return offsets
+}
+
+type CensusOptions struct {
+	Rule         engine.Rule
+	Neighborhood Neighborhood
+	MaxPeriod    int
+}
+
+func DefaultCensusOptions() CensusOptions {
+	return CensusOptions{
+		Rule:         engine.ConwayRule(),
+		Neighborhood: DefaultNeighborhood(),
+		MaxPeriod:    DefaultCensusMaxPeriod,
+	}
+}
+
+// CensusEntry counts one kind of object. Name is empty for objects without
+// a common name.
+type CensusEntry struct {
+	Code  string `json:"apgcode"`
+	Name  string `json:"name,omitempty"`
+	Count int    `json:"count"`
+}
+
+// Census tallies the objects on a board, most common first.
+type Census struct {
+	Objects []CensusEntry `json:"objects"`
+	Total   int           `json:"total"`
+}
+
+// TakeCensus splits the board into islands of live cells, wrapping around
+// its edges like the simulation does, and identifies each one by running it
+// alone on an unbounded plane.
+func TakeCensus(board engine.Board, options CensusOptions) Census {
+	options = options.withDefaults()
+	return identify(islands(board, options.Neighborhood.offsets()), options)
+}
+
+// TakeUniverseCensus is TakeCensus for an unbounded universe.
+func TakeUniverseCensus(u engine.Universe, options CensusOptions) Census {
+	options = options.withDefaults()
+	return identify(universeIslands(u, options.Neighborhood.offsets()), options)
+}
+
+func (o CensusOptions) withDefaults() CensusOptions {
+	if o.MaxPeriod <= 0 {
+		o.MaxPeriod = DefaultCensusMaxPeriod
+	}
+	if o.Neighborhood.Range <= 0 {
+		o.Neighborhood = DefaultNeighborhood()
+	}
+	return o
+}
+
+// identify codes each island. Islands that do not repeat alone are often
+// pieces of one object whose phases are not all connected, such as a toad,
+// so they are grouped once more with a one step wider neighborhood.
+func identify(islands [][]engine.Cell, options CensusOptions) Census {
+	counts := map[string]int{}
+	known := map[string]string{}
+	var leftovers []engine.Cell
+	for _, island := range islands {
+		code, ok := identifyIsland(island, options, known)
+		if !ok {
+			leftovers = append(leftovers, island...)
+			continue
+		}
+		counts[code]++
+	}
+	wider := Neighborhood{Shape: options.Neighborhood.Shape, Range: options.Neighborhood.Range + 1}
+	for _, island := range universeIslands(engine.NewUniverse(leftovers...), wider.offsets()) {
+		code, ok := identifyIsland(island, options, known)
+		if !ok {
+			code = UnidentifiedCode
+		}
+		counts[code]++
+	}
+	return NewCensus(counts)
+}
+
+func identifyIsland(island []engine.Cell, options CensusOptions, known map[string]string) (string, bool) {
+	object := engine.NewUniverse(island...)
+	key := wechslerCode(orientations(object.Cells())[0])
+	code, seen := known[key]
+	if !seen {
+		code, _ = Apgcode(object, options.Rule, options.MaxPeriod)
+		known[key] = code
+	}
+	return code, code != ""
+}
+
+// NewCensus builds a census from counts by apgcode, for instance to add up
+// the censuses of many soups.
+func NewCensus(counts map[string]int) Census {
+	census := Census{Objects: make([]CensusEntry, 0, len(counts))}
+	for code, count := range counts {
+		census.Objects = append(census.Objects, CensusEntry{Code: code, Name: ObjectName(code), Count: count})
+		census.Total += count
+	}
+	sort.Slice(census.Objects, func(i, j int) bool {
+		a, b := census.Objects[i], census.Objects[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Code < b.Code
+	})
+	return census
+}
+
+// islands groups the live cells into connected components. Cells get
+// coordinates relative to where their island was first reached, so an
+// island straddling an edge comes out in one piece.
+func islands(board engine.Board, offsets []engine.Cell) [][]engine.Cell {
+	width, height := board.Width(), board.Height()
+	visited := make([]bool, width*height)
+	var result [][]engine.Cell
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if visited[y*width+x] || !board.IsAlive(x, y) {
+				continue
+			}
+			visited[y*width+x] = true
+			island := []engine.Cell{{X: x, Y: y}}
+			for next := 0; next < len(island); next++ {
+				c := island[next]
+				for _, offset := range offsets {
+					nx, ny := c.X+offset.X, c.Y+offset.Y
+					wx, wy := (nx%width+width)%width, (ny%height+height)%height
+					if visited[wy*width+wx] || !board.IsAlive(wx, wy) {
+						continue
+					}
+					visited[wy*width+wx] = true
+					island = append(island, engine.Cell{X: nx, Y: ny})
+				}
+			}
+			result = append(result, island)
+		}
+	}
+	return result
+}
+
+func universeIslands(u engine.Universe, offsets []engine.Cell) [][]engine.Cell {
+	visited := make(map[engine.Cell]bool, u.Population())
+	var result [][]engine.Cell
+	for _, start := range u.Cells() {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		island := []engine.Cell{start}
+		for next := 0; next < len(island); next++ {
+			for _, offset := range offsets {
+				c := engine.Cell{X: island[next].X + offset.X, Y: island[next].Y + offset.Y}
+				if visited[c] || !u.IsAlive(c.X, c.Y) {
+					continue
+				}
+				visited[c] = true
+				island = append(island, c)
+			}
+		}
+		result = append(result, island)
+	}
+	return result
+}
+
+// String reads like "12 blocks, 4 blinkers, 1 glider"; objects without a
+// common name are listed by apgcode.
+func (c Census) String() string {
+	if len(c.Objects) == 0 {
+		return "empty"
+	}
+	parts := make([]string, len(c.Objects))
+	for i, entry := range c.Objects {
+		label := entry.Code
+		if names, ok := objectNames[entry.Code]; ok {
+			label = names.plural
+			if entry.Count == 1 {
+				label = names.singular
+			}
+		}
+		parts[i] = fmt.Sprintf("%d %s", entry.Count, label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteCSV writes one apgcode,name,count row per object after a header.
+func (c Census) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"apgcode", "name", "count"}); err != nil {
+		return err
+	}
+	for _, entry := range c.Objects {
+		if err := writer.Write([]string{entry.Code, entry.Name, strconv.Itoa(entry.Count)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (c Census) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+	"gol-on-cli/internal/pattern"
+)
+
+func board(t *testing.T, width, height int, rle string) engine.Board {
+	t.Helper()
+	p, err := pattern.Decode(pattern.FormatRLE, rle)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", rle, err)
+	}
+	placed := engine.NewBoard(width, height)
+	for y := 0; y < p.Board.Height(); y++ {
+		for x := 0; x < p.Board.Width(); x++ {
+			if p.Board.IsAlive(x, y) {
+				placed.SetAlive(x, y, true)
+			}
+		}
+	}
+	return placed
+}
+
+func TestShouldTallyObjectsOnABoard(t *testing.T) {
+	// two blocks, a blinker and a glider, well apart
+	b := board(t, 30, 20, "x = 17, y = 14\n2o5b2o$2o5b2o4$3o6$15bo$16bo$14b3o!")
+
+	census := TakeCensus(b, DefaultCensusOptions())
+
+	if got := census.String(); got != "2 blocks, 1 blinker, 1 glider" {
+		t.Fatalf("unexpected census %q", got)
+	}
+	if census.Total != 4 || census.Objects[0].Code != "xs4_33" || census.Objects[0].Name != "block" {
+		t.Fatalf("unexpected census %+v", census)
+	}
+}
+
+func TestShouldJoinIslandsAcrossBoardEdges(t *testing.T) {
+	// a block split over the left and right edges, and over the top and bottom
+	b := engine.NewBoard(10, 10)
+	for _, c := range []engine.Cell{{X: 0, Y: 0}, {X: 9, Y: 0}, {X: 0, Y: 9}, {X: 9, Y: 9}} {
+		b.SetAlive(c.X, c.Y, true)
+	}
+
+	if got := TakeCensus(b, DefaultCensusOptions()).String(); got != "1 block" {
+		t.Fatalf("expected one block across the corners, got %q", got)
+	}
+}
+
+func TestShouldGroupIslandsByNeighborhood(t *testing.T) {
+	// a beacon is two blocks touching at a corner
+	beacon := board(t, 10, 10, "x = 4, y = 4\n2o$2o$2b2o$2b2o!")
+	options := DefaultCensusOptions()
+
+	if got := TakeCensus(beacon, options).String(); got != "1 beacon" {
+		t.Fatalf("expected a Moore census to see a beacon, got %q", got)
+	}
+	options.Neighborhood, _ = ParseNeighborhood("vonneumann")
+	if got := TakeCensus(beacon, options).String(); got != "2 blocks" {
+		t.Fatalf("expected a von Neumann census to see two blocks, got %q", got)
+	}
+
+	// blocks one cell apart only join with a wider range
+	pair := board(t, 10, 10, "x = 5, y = 2\n2ob2o$2ob2o!")
+	options.Neighborhood, _ = ParseNeighborhood("moore:2")
+	if got := TakeCensus(pair, options).String(); got != "1 bi-block" {
+		t.Fatalf("expected a range 2 census to see a bi-block, got %q", got)
+	}
+}
+
+func TestShouldParseNeighborhoods(t *testing.T) {
+	for text, want := range map[string]string{"moore": "moore", "Von-Neumann": "vonneumann", "moore:2": "moore:2", "vonneumann:3": "vonneumann:3"} {
+		n, err := ParseNeighborhood(text)
+		if err != nil || n.String() != want {
+			t.Fatalf("expected %q to parse as %s, got %v %v", text, want, n, err)
+		}
+	}
+	for _, text := range []string{"hex", "moore:0", "moore:x"} {
+		if _, err := ParseNeighborhood(text); err == nil {
+			t.Fatalf("expected %q to be rejected", text)
+		}
+	}
+}
+
+func TestShouldExportCensusAsCSVAndJSON(t *testing.T) {
+	census := TakeCensus(board(t, 20, 10, "x = 10, y = 2\n2o5b3o$2o!"), DefaultCensusOptions())
+
+	var csvOut bytes.Buffer
+	if err := census.WriteCSV(&csvOut); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	if csvOut.String() != "apgcode,name,count\nxp2_7,blinker,1\nxs4_33,block,1\n" {
+		t.Fatalf("unexpected CSV %q", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := census.WriteJSON(&jsonOut); err != nil {
+		t.Fatalf("failed to write JSON: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"apgcode": "xs4_33"`) || !strings.Contains(jsonOut.String(), `"total": 2`) {
+		t.Fatalf("unexpected JSON %s", jsonOut.String())
+	}
+}
+
+func TestShouldRegroupPiecesOfObjectsThatDoNotRepeatAlone(t *testing.T) {
+	// this toad phase is two diagonal triples that are not touching
+	toad := board(t, 10, 10, "x = 4, y = 4\n2bo$o2bo$o2bo$bo!")
+
+	if got := TakeCensus(toad, DefaultCensusOptions()).String(); got != "1 toad" {
+		t.Fatalf("expected the pieces to be counted as one toad, got %q", got)
+	}
+}
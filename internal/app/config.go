@@ -1,25 +1,334 @@
 package app
 
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gol-on-cli/internal/analysis"
+)
+
 type SeedMode string
 
 type ColorMode string
 
 const (
-	SeedModeRandom      SeedMode  = "random"
-	ColorModeTrueColor  ColorMode = "truecolor"
-	defaultFPS                    = 5
+	SeedModeRandom     SeedMode  = "random"
+	ColorModeTrueColor ColorMode = "truecolor"
+	ColorMode256       ColorMode = "256"
+	defaultFPS                   = 5
+	defaultRestartFade           = 400 * time.Millisecond
 )
 
 type Config struct {
 	FPS       int
+	Seed      string
+	Trail     int
 	SeedMode  SeedMode
 	ColorMode ColorMode
+	Soup      SoupSpec
+	Restart   RestartConfig
+	Census    CensusConfig
+}
+
+type RestartConfig struct {
+	On             string
+	Linger         int
+	MinPopulation  int
+	MaxGenerations int
+	Fade           time.Duration
+}
+
+type CensusConfig struct {
+	Neighborhood string
 }
 
 func DefaultConfig() Config {
 	return Config{
 		FPS:       defaultFPS,
+		Seed:      "0",
 		SeedMode:  SeedModeRandom,
 		ColorMode: ColorModeTrueColor,
+		Soup:      DefaultSoupSpec(),
+		Restart: RestartConfig{
+			On:     "cycle",
+			Linger: DefaultRestartLinger,
+			Fade:   defaultRestartFade,
+		},
+		Census: CensusConfig{Neighborhood: analysis.DefaultNeighborhood().String()},
+	}
+}
+
+func (c RestartConfig) Policy() (RestartPolicy, error) {
+	return ParseRestartPolicy(c.On, RestartOptions{
+		Linger:         c.Linger,
+		MinPopulation:  c.MinPopulation,
+		MaxGenerations: c.MaxGenerations,
+	})
+}
+
+// setting is one configurable value. Its key is used in config.toml
+// ("section.name") and, upper-cased with a GOL_ prefix, as its environment
+// variable.
+type setting struct {
+	key    string
+	quoted bool
+	get    func(c *Config) string
+	set    func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{key: "fps", get: func(c *Config) string { return strconv.Itoa(c.FPS) }, set: func(c *Config, v string) error { return parseInt(v, &c.FPS) }},
+	{key: "seed", quoted: true, get: func(c *Config) string { return c.Seed }, set: func(c *Config, v string) error {
+		c.Seed = v
+		return nil
+	}},
+	{key: "trail", get: func(c *Config) string { return strconv.Itoa(c.Trail) }, set: func(c *Config, v string) error { return parseInt(v, &c.Trail) }},
+	{key: "color", quoted: true, get: func(c *Config) string { return string(c.ColorMode) }, set: func(c *Config, v string) error {
+		c.ColorMode = ColorMode(v)
+		return nil
+	}},
+	{key: "soup.density", get: func(c *Config) string { return strconv.FormatFloat(c.Soup.Density, 'g', -1, 64) }, set: func(c *Config, v string) error {
+		density, err := strconv.ParseFloat(v, 64)
+		c.Soup.Density = density
+		return err
+	}},
+	{key: "soup.size", quoted: true, get: func(c *Config) string { return c.Soup.Size() }, set: func(c *Config, v string) error {
+		var err error
+		c.Soup.Width, c.Soup.Height, err = ParseSoupSize(v)
+		return err
+	}},
+	{key: "soup.placement", quoted: true, get: func(c *Config) string { return string(c.Soup.Placement) }, set: func(c *Config, v string) error {
+		c.Soup.Placement = Placement(v)
+		return nil
+	}},
+	{key: "soup.symmetry", quoted: true, get: func(c *Config) string { return string(c.Soup.Symmetry) }, set: func(c *Config, v string) error {
+		var err error
+		c.Soup.Symmetry, err = ParseSymmetry(v)
+		return err
+	}},
+	{key: "restart.on", quoted: true, get: func(c *Config) string { return c.Restart.On }, set: func(c *Config, v string) error {
+		c.Restart.On = v
+		return nil
+	}},
+	{key: "restart.linger", get: func(c *Config) string { return strconv.Itoa(c.Restart.Linger) }, set: func(c *Config, v string) error { return parseInt(v, &c.Restart.Linger) }},
+	{key: "restart.population", get: func(c *Config) string { return strconv.Itoa(c.Restart.MinPopulation) }, set: func(c *Config, v string) error { return parseInt(v, &c.Restart.MinPopulation) }},
+	{key: "restart.generations", get: func(c *Config) string { return strconv.Itoa(c.Restart.MaxGenerations) }, set: func(c *Config, v string) error { return parseInt(v, &c.Restart.MaxGenerations) }},
+	{key: "restart.fade", quoted: true, get: func(c *Config) string { return c.Restart.Fade.String() }, set: func(c *Config, v string) error {
+		var err error
+		c.Restart.Fade, err = time.ParseDuration(v)
+		return err
+	}},
+	{key: "census.neighborhood", quoted: true, get: func(c *Config) string { return c.Census.Neighborhood }, set: func(c *Config, v string) error {
+		c.Census.Neighborhood = v
+		return nil
+	}},
+}
+
+func parseInt(value string, target *int) error {
+	parsed, err := strconv.Atoi(value)
+	*target = parsed
+	return err
+}
+
+// ConfigKeys lists every setting key in config file order.
+func ConfigKeys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// ConfigEnvName is the environment variable for a setting key, e.g.
+// GOL_FPS or GOL_SOUP_DENSITY.
+func ConfigEnvName(key string) string {
+	return "GOL_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// Set assigns one setting from its text form; ranges are left to Validate.
+func (c *Config) Set(key, value string) error {
+	s, ok := findSetting(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err := s.set(c, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %q", key, value)
+	}
+	return nil
+}
+
+func (c Config) Get(key string) string {
+	if s, ok := findSetting(key); ok {
+		return s.get(&c)
+	}
+	return ""
+}
+
+// ApplyEnv overrides settings from GOL_* variables and returns the keys it
+// changed.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) ([]string, error) {
+	var applied []string
+	for _, s := range settings {
+		name := ConfigEnvName(s.key)
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := c.Set(s.key, value); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		applied = append(applied, s.key)
+	}
+	return applied, nil
+}
+
+func (c Config) Validate() error {
+	if c.FPS <= 0 {
+		return fmt.Errorf("fps: must be greater than zero")
+	}
+	if c.Seed == "" {
+		return fmt.Errorf("seed: must not be empty")
+	}
+	if c.Trail < 0 {
+		return fmt.Errorf("trail: must be zero or greater")
+	}
+	if c.ColorMode != ColorModeTrueColor && c.ColorMode != ColorMode256 {
+		return fmt.Errorf("color: unknown mode %q (available: %s, %s)", c.ColorMode, ColorModeTrueColor, ColorMode256)
+	}
+	if err := c.Soup.Validate(); err != nil {
+		return fmt.Errorf("soup: %v", err)
+	}
+	if c.Restart.Linger < 0 || c.Restart.MinPopulation < 0 || c.Restart.MaxGenerations < 0 {
+		return fmt.Errorf("restart: linger, population and generations must be zero or greater")
+	}
+	if c.Restart.Fade < 0 {
+		return fmt.Errorf("restart.fade: must be zero or greater")
+	}
+	if _, err := c.Restart.Policy(); err != nil {
+		return fmt.Errorf("invalid restart policy: %v", err)
+	}
+	if _, err := analysis.ParseNeighborhood(c.Census.Neighborhood); err != nil {
+		return fmt.Errorf("census.neighborhood: %v", err)
+	}
+	return nil
+}
+
+// WriteTOML prints the configuration in the format ParseConfig reads. When
+// origins is given, each line notes where its value came from.
+func (c Config) WriteTOML(w io.Writer, origins map[string]string) error {
+	section := ""
+	for _, s := range settings {
+		name := s.key
+		if group, key, ok := strings.Cut(s.key, "."); ok {
+			if group != section {
+				section = group
+				if _, err := fmt.Fprintf(w, "\n[%s]\n", section); err != nil {
+					return err
+				}
+			}
+			name = key
+		}
+		value := s.get(&c)
+		if s.quoted {
+			value = strconv.Quote(value)
+		}
+		line := name + " = " + value
+		if origin := origins[s.key]; origin != "" {
+			line += " # " + origin
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseConfig reads a small TOML subset (sections, key = value, quoted
+// strings and numbers) on top of DefaultConfig.
+func ParseConfig(r io.Reader) (Config, error) {
+	config := DefaultConfig()
+	_, err := config.ApplyTOML(r)
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// ApplyTOML reads the same subset as ParseConfig over the current values and
+// returns the keys it set.
+func (c *Config) ApplyTOML(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	section := ""
+	line := 0
+	var applied []string
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("config line %d: expected \"key = value\"", line)
+		}
+		key = strings.TrimSpace(key)
+		if section != "" {
+			key = section + "." + key
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("config line %d: invalid string %s: %v", line, value, err)
+			}
+			value = unquoted
+		}
+		if err := c.Set(key, value); err != nil {
+			return nil, fmt.Errorf("config line %d: %v", line, err)
+		}
+		applied = append(applied, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// stripComment drops a trailing "# ..." outside quoted strings.
+func stripComment(line string) string {
+	quoted, escaped := false, false
+	for i, r := range line {
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = quoted
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return line[:i]
+			}
+		}
 	}
+	return line
 }
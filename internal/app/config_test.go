@@ -1,6 +1,10 @@
 package app
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestShouldProvideDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
@@ -17,3 +21,83 @@ func TestShouldProvideDefaultConfig(t *testing.T) {
 		t.Fatalf("expected default color mode to be %q, got %q", ColorModeTrueColor, cfg.ColorMode)
 	}
 }
+
+func TestShouldParseRestartSectionFromConfigFile(t *testing.T) {
+	cfg, err := ParseConfig(strings.NewReader("# screensaver\nfps = 12\n\n[restart]\non = \"cycle,generations\"\nlinger = 20\ngenerations = 3000\nfade = \"1s\"\n"))
+	if err != nil {
+		t.Fatalf("expected config to parse, got %v", err)
+	}
+
+	if cfg.FPS != 12 || cfg.Restart.On != "cycle,generations" || cfg.Restart.Linger != 20 || cfg.Restart.MaxGenerations != 3000 || cfg.Restart.Fade != time.Second {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if _, err := cfg.Restart.Policy(); err != nil {
+		t.Fatalf("expected restart policy from config, got %v", err)
+	}
+}
+
+func TestShouldReportConfigLineForUnknownSetting(t *testing.T) {
+	_, err := ParseConfig(strings.NewReader("[restart]\nspeed = 3\n"))
+
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line-numbered error, got %v", err)
+	}
+}
+
+func TestShouldApplyEnvironmentOverConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	env := map[string]string{"GOL_SOUP_DENSITY": "0.25", "GOL_RESTART_ON": "still"}
+
+	applied, err := cfg.ApplyEnv(func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	})
+
+	if err != nil || len(applied) != 2 || cfg.Soup.Density != 0.25 || cfg.Restart.On != "still" {
+		t.Fatalf("unexpected env result %v %+v (%v)", applied, cfg, err)
+	}
+}
+
+func TestShouldRoundTripPrintedConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = "k_a#b"
+	cfg.Soup.Width, cfg.Soup.Height = 0, 0
+	var out strings.Builder
+
+	if err := cfg.WriteTOML(&out, map[string]string{"seed": "--seed-string"}); err != nil {
+		t.Fatalf("expected config to print, got %v", err)
+	}
+	parsed, err := ParseConfig(strings.NewReader(out.String()))
+	if err != nil || parsed != cfg {
+		t.Fatalf("expected printed config to parse back, got %+v (%v)\n%s", parsed, err, out.String())
+	}
+}
+
+func TestShouldRoundTripQuotesAndBackslashesInStrings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Seed = `k_"quoted" \ # not a comment`
+	var out strings.Builder
+
+	if err := cfg.WriteTOML(&out, nil); err != nil {
+		t.Fatalf("expected config to print, got %v", err)
+	}
+	parsed, err := ParseConfig(strings.NewReader(out.String()))
+	if err != nil || parsed.Seed != cfg.Seed {
+		t.Fatalf("expected seed %q to read back, got %q (%v)\n%s", cfg.Seed, parsed.Seed, err, out.String())
+	}
+	if _, err := ParseConfig(strings.NewReader(`seed = "unterminated`)); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected a malformed string to fail, got %v", err)
+	}
+}
+
+func TestShouldRejectOutOfRangeSettings(t *testing.T) {
+	for key, value := range map[string]string{"trail": "-1", "color": "mono", "soup.placement": "corner", "restart.on": "sometimes"} {
+		cfg := DefaultConfig()
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("expected %s to be accepted before validation, got %v", key, err)
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("expected %s = %s to fail validation", key, value)
+		}
+	}
+}
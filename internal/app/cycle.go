@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"gol-on-cli/internal/engine"
+)
+
+const (
+	cycleWindow        = 64
+	maxCycleCandidates = 64
+)
+
+// Cycle describes a board that repeats itself after Period generations,
+// shifted by DX, DY cells (zero for oscillators and still lifes).
+type Cycle struct {
+	Period int
+	DX     int
+	DY     int
+}
+
+func (c Cycle) Translated() bool {
+	return c.DX != 0 || c.DY != 0
+}
+
+func (c Cycle) String() string {
+	switch {
+	case c.Translated():
+		return fmt.Sprintf("ship p%d (%+d,%+d)", c.Period, c.DX, c.DY)
+	case c.Period == 1:
+		return "still"
+	}
+	return fmt.Sprintf("osc p%d", c.Period)
+}
+
+type cycleEntry struct {
+	generation int
+	key        uint64
+	bits       []uint64
+	rowShift   int
+	rowPeriod  int
+	colShift   int
+	colPeriod  int
+}
+
+// cycleDetector remembers the last cycleWindow boards under a
+// translation-invariant key: the row and column population profiles rotated
+// to their lexicographically smallest form. Boards sharing a key are then
+// compared exactly under each toroidal shift that aligns the profiles.
+type cycleDetector struct {
+	window  int
+	entries []cycleEntry
+}
+
+func newCycleDetector(window int) *cycleDetector {
+	return &cycleDetector{window: window}
+}
+
+func (d *cycleDetector) reset() {
+	d.entries = nil
+}
+
+func (d *cycleDetector) observe(board engine.Board, generation int) (Cycle, bool) {
+	entry := newCycleEntry(board, generation)
+	cycle, found := d.match(board, entry)
+	d.entries = append(d.entries, entry)
+	if len(d.entries) > d.window {
+		d.entries = d.entries[len(d.entries)-d.window:]
+	}
+	return cycle, found
+}
+
+func (d *cycleDetector) match(board engine.Board, current cycleEntry) (Cycle, bool) {
+	width, height := board.Width(), board.Height()
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		past := d.entries[i]
+		if past.key != current.key || len(past.bits) != len(current.bits) {
+			continue
+		}
+		period := current.generation - past.generation
+		if period <= 0 {
+			continue
+		}
+		candidates := 0
+	shifts:
+		for dy := mod(current.rowShift-past.rowShift, past.rowPeriod); dy < height; dy += past.rowPeriod {
+			for dx := mod(current.colShift-past.colShift, past.colPeriod); dx < width; dx += past.colPeriod {
+				if candidates++; candidates > maxCycleCandidates {
+					break shifts
+				}
+				if shiftedEqual(board, past.bits, dx, dy) {
+					return Cycle{Period: period, DX: signedOffset(dx, width), DY: signedOffset(dy, height)}, true
+				}
+			}
+		}
+	}
+	return Cycle{}, false
+}
+
+func newCycleEntry(board engine.Board, generation int) cycleEntry {
+	width, height := board.Width(), board.Height()
+	rows := make([]int, height)
+	cols := make([]int, width)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if board.IsAlive(x, y) {
+				rows[y]++
+				cols[x]++
+			}
+		}
+	}
+	entry := cycleEntry{generation: generation, bits: packBoard(board)}
+	entry.rowShift, entry.rowPeriod = minimalRotation(rows)
+	entry.colShift, entry.colPeriod = minimalRotation(cols)
+
+	hash := fnv.New64a()
+	buf := make([]byte, 0, 4*(width+height)+8)
+	buf = appendInt(buf, width)
+	buf = appendInt(buf, height)
+	for i := range rows {
+		buf = appendInt(buf, rows[(entry.rowShift+i)%height])
+	}
+	for i := range cols {
+		buf = appendInt(buf, cols[(entry.colShift+i)%width])
+	}
+	hash.Write(buf)
+	entry.key = hash.Sum64()
+	return entry
+}
+
+// minimalRotation returns the start of the lexicographically smallest
+// rotation of values and the profile's own rotational period.
+func minimalRotation(values []int) (shift, period int) {
+	n := len(values)
+	if n == 0 {
+		return 0, 1
+	}
+	for r := 1; r < n; r++ {
+		if compareRotations(values, r, shift) < 0 {
+			shift = r
+		}
+	}
+	period = n
+	for r := 1; r < n; r++ {
+		if n%r == 0 && compareRotations(values, r, 0) == 0 {
+			period = r
+			break
+		}
+	}
+	return shift, period
+}
+
+func compareRotations(values []int, a, b int) int {
+	n := len(values)
+	for i := 0; i < n; i++ {
+		left, right := values[(a+i)%n], values[(b+i)%n]
+		if left != right {
+			return left - right
+		}
+	}
+	return 0
+}
+
+// shiftedEqual reports whether board equals the packed board moved by dx, dy
+// on the torus.
+func shiftedEqual(board engine.Board, bits []uint64, dx, dy int) bool {
+	width, height := board.Width(), board.Height()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			index := mod(y-dy, height)*width + mod(x-dx, width)
+			if board.IsAlive(x, y) != (bits[index/64]&(1<<(index%64)) != 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func signedOffset(offset, size int) int {
+	if offset > size/2 {
+		return offset - size
+	}
+	return offset
+}
+
+func mod(value, size int) int {
+	if size == 0 {
+		return 0
+	}
+	return ((value % size) + size) % size
+}
+
+func appendInt(buf []byte, value int) []byte {
+	return append(buf, byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func detectCycle(board engine.Board, generations int) (Cycle, bool) {
+	detector := newCycleDetector(cycleWindow)
+	detector.observe(board, 0)
+	for generation := 1; generation <= generations; generation++ {
+		board = board.NextGeneration()
+		if cycle, ok := detector.observe(board, generation); ok {
+			return cycle, true
+		}
+	}
+	return Cycle{}, false
+}
+
+func TestShouldDetectStillLife(t *testing.T) {
+	block := engine.NewBoard(6, 6)
+	for _, p := range [][2]int{{2, 2}, {3, 2}, {2, 3}, {3, 3}} {
+		block.SetAlive(p[0], p[1], true)
+	}
+
+	cycle, ok := detectCycle(block, 3)
+
+	if !ok || cycle.Period != 1 || cycle.Translated() || cycle.String() != "still" {
+		t.Fatalf("expected still life, got %+v %v", cycle, ok)
+	}
+}
+
+func TestShouldDetectBlinkerAsPeriodTwoOscillator(t *testing.T) {
+	blinker := engine.NewBoard(7, 7)
+	for x := 2; x <= 4; x++ {
+		blinker.SetAlive(x, 3, true)
+	}
+
+	cycle, ok := detectCycle(blinker, 5)
+
+	if !ok || cycle.Period != 2 || cycle.Translated() {
+		t.Fatalf("expected period 2 oscillator, got %+v %v", cycle, ok)
+	}
+}
+
+func TestShouldFindShiftBelowTheAlignedProfileOffset(t *testing.T) {
+	// the column profiles line up at an offset of 6, the board moved 2
+	past := engine.NewBoard(8, 8)
+	current := engine.NewBoard(8, 8)
+	for _, p := range [][2]int{{2, 1}, {2, 2}, {6, 5}, {6, 7}} {
+		past.SetAlive(p[0], p[1], true)
+		current.SetAlive((p[0]+2)%8, p[1], true)
+	}
+	detector := newCycleDetector(cycleWindow)
+	detector.observe(past, 0)
+
+	cycle, ok := detector.observe(current, 1)
+
+	if !ok || cycle.DX != 2 || cycle.DY != 0 {
+		t.Fatalf("expected shift (+2,+0), got %+v %v", cycle, ok)
+	}
+}
+
+func TestShouldDetectGliderAsSpaceshipAcrossTorusWrap(t *testing.T) {
+	glider := engine.NewBoard(10, 8)
+	for _, p := range [][2]int{{8, 5}, {9, 6}, {7, 7}, {8, 7}, {9, 7}} {
+		glider.SetAlive(p[0], p[1], true)
+	}
+
+	cycle, ok := detectCycle(glider, 8)
+
+	if !ok || cycle.Period != 4 || cycle.DX != 1 || cycle.DY != 1 {
+		t.Fatalf("expected glider p4 (+1,+1), got %+v %v", cycle, ok)
+	}
+	if cycle.String() != "ship p4 (+1,+1)" {
+		t.Fatalf("unexpected cycle description %q", cycle.String())
+	}
+}
+
+func TestShouldRestartAfterLingeringInOscillation(t *testing.T) {
+	sim := emptySimulation(7, 7)
+	for x := 2; x <= 4; x++ {
+		sim.SetCell(x, 3, true)
+	}
+
+	for i := 0; i < 10; i++ {
+		sim.Tick()
+	}
+	if cycle, ok := sim.Cycle(); !ok || cycle.Period != 2 {
+		t.Fatalf("expected simulation to report the blinker, got %+v %v", cycle, ok)
+	}
+	for i := 0; i < 100; i++ {
+		sim.Tick()
+	}
+	if sim.Generation() >= 110 {
+		t.Fatalf("expected oscillating soup to auto-restart, still at generation %d", sim.Generation())
+	}
+}
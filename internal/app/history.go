@@ -0,0 +1,99 @@
+package app
+
+import "gol-on-cli/internal/engine"
+
+const (
+	maxUndoBytes = 32 << 20
+	maxUndoDepth = 256
+)
+
+type historyEntry struct {
+	board      engine.Board
+	generation int
+	soupID     string
+}
+
+type undoHistory struct {
+	undo []historyEntry
+	redo []historyEntry
+}
+
+func undoDepthFor(width, height int) int {
+	cells := width * height
+	if cells <= 0 {
+		return maxUndoDepth
+	}
+	depth := maxUndoBytes / cells
+	if depth < 1 {
+		return 1
+	}
+	if depth > maxUndoDepth {
+		return maxUndoDepth
+	}
+	return depth
+}
+
+func (h *undoHistory) push(entry historyEntry) {
+	h.undo = append(h.undo, entry)
+	h.redo = nil
+	h.trim(entry.board.Width(), entry.board.Height())
+}
+
+func (h *undoHistory) trim(width, height int) {
+	depth := undoDepthFor(width, height)
+	if len(h.undo) > depth {
+		h.undo = append([]historyEntry(nil), h.undo[len(h.undo)-depth:]...)
+	}
+}
+
+// Checkpoint pushes the board to the undo history and starts an edit stroke.
+func (s *Simulation) Checkpoint() {
+	s.flushEdits()
+	s.history.push(historyEntry{board: s.board, generation: s.generation, soupID: s.soupID})
+	s.ownsBoard = false
+}
+
+func (s *Simulation) CanUndo() bool {
+	return len(s.history.undo) > 0
+}
+
+func (s *Simulation) CanRedo() bool {
+	return len(s.history.redo) > 0
+}
+
+func (s *Simulation) Undo() bool {
+	if !s.CanUndo() {
+		return false
+	}
+	last := len(s.history.undo) - 1
+	entry := s.history.undo[last]
+	s.history.undo = s.history.undo[:last]
+	s.history.redo = append(s.history.redo, historyEntry{board: s.board, generation: s.generation, soupID: s.soupID})
+	s.restoreEntry(entry)
+	return true
+}
+
+func (s *Simulation) Redo() bool {
+	if !s.CanRedo() {
+		return false
+	}
+	last := len(s.history.redo) - 1
+	entry := s.history.redo[last]
+	s.history.redo = s.history.redo[:last]
+	s.history.undo = append(s.history.undo, historyEntry{board: s.board, generation: s.generation, soupID: s.soupID})
+	s.restoreEntry(entry)
+	return true
+}
+
+func (s *Simulation) restoreEntry(entry historyEntry) {
+	board := entry.board
+	if board.Width() != s.width || board.Height() != s.height {
+		board = board.Crop(0, 0, s.width, s.height)
+	}
+	s.board = board
+	s.generation = entry.generation
+	s.soupID = entry.soupID
+	s.stableGenerations = 0
+	s.replaced++
+	s.resetTimeline()
+}
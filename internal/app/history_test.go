@@ -0,0 +1,153 @@
+package app
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldUndoAndRedoCheckpointedEdit(t *testing.T) {
+	sim := emptySimulation(3, 3)
+
+	sim.Checkpoint()
+	sim.SetCell(1, 1, true)
+
+	if !sim.Undo() || sim.Board().IsAlive(1, 1) {
+		t.Fatalf("expected undo to remove the edited cell")
+	}
+	if !sim.Redo() || !sim.Board().IsAlive(1, 1) {
+		t.Fatalf("expected redo to restore the edited cell")
+	}
+	if sim.CanRedo() {
+		t.Fatalf("expected redo stack to be empty after redo")
+	}
+}
+
+func TestShouldUndoRestartBackToPreviousSoupAndGeneration(t *testing.T) {
+	sim := NewSimulation(10, 10, 5)
+	sim.Tick()
+	sim.Tick()
+	before := sim.Board()
+
+	sim.Restart()
+	sim.Undo()
+
+	if sim.Generation() != 2 || !boardsEqual(before, sim.Board()) {
+		t.Fatalf("expected undo to restore generation 2 soup, got generation %d", sim.Generation())
+	}
+}
+
+func TestShouldRecoverCellsLostByShrinkingResize(t *testing.T) {
+	sim := emptySimulation(6, 6)
+	sim.SetCell(5, 5, true)
+
+	sim.Resize(3, 3)
+	sim.Resize(6, 6)
+	sim.Undo()
+	sim.Undo()
+
+	if !sim.Board().IsAlive(5, 5) {
+		t.Fatalf("expected undo to recover cell cropped by resize")
+	}
+	if sim.Board().Width() != 6 {
+		t.Fatalf("expected restored board to match current size, got width %d", sim.Board().Width())
+	}
+}
+
+func TestShouldClearRedoAfterNewCheckpoint(t *testing.T) {
+	sim := emptySimulation(3, 3)
+	sim.Checkpoint()
+	sim.SetCell(0, 0, true)
+	sim.Undo()
+
+	sim.Checkpoint()
+
+	if sim.CanRedo() {
+		t.Fatalf("expected new checkpoint to discard redo history")
+	}
+}
+
+func TestShouldBoundUndoDepthByBoardSize(t *testing.T) {
+	if depth := undoDepthFor(8000, 8000); depth != 1 {
+		t.Fatalf("expected huge boards to keep a single undo step, got %d", depth)
+	}
+	if depth := undoDepthFor(10, 10); depth != maxUndoDepth {
+		t.Fatalf("expected small boards to use max depth, got %d", depth)
+	}
+
+	sim := NewSimulationWithFactory(2, 2, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	for i := 0; i < maxUndoDepth+10; i++ {
+		sim.Checkpoint()
+	}
+	if len(sim.history.undo) != maxUndoDepth {
+		t.Fatalf("expected undo stack capped at %d, got %d", maxUndoDepth, len(sim.history.undo))
+	}
+}
+
+func TestShouldCopyBoardOncePerEditStroke(t *testing.T) {
+	sim := emptySimulation(4, 4)
+	sim.Pause()
+	before := sim.Board()
+
+	sim.Checkpoint()
+	sim.SetCell(0, 0, true)
+	sim.SetCell(1, 0, true)
+	sim.SetCell(2, 0, true)
+	sim.Checkpoint()
+	stroke := sim.Board()
+	sim.SetCell(3, 3, true)
+
+	if before.Population() != 0 || stroke.Population() != 3 {
+		t.Fatalf("expected earlier boards to keep their cells, got %d and %d", before.Population(), stroke.Population())
+	}
+	if !sim.Undo() || sim.Board().Population() != 3 {
+		t.Fatalf("expected undo to drop only the second stroke, got %d cells", sim.Board().Population())
+	}
+	if !sim.Undo() || sim.Board().Population() != 0 {
+		t.Fatalf("expected undo to drop the whole first stroke, got %d cells", sim.Board().Population())
+	}
+}
+
+func TestShouldRecordEditStrokeInTimelineBeforeNextGeneration(t *testing.T) {
+	sim := emptySimulation(5, 5)
+	sim.Checkpoint()
+	for x := 1; x <= 3; x++ {
+		sim.SetCell(x, 2, true)
+	}
+
+	sim.Tick()
+	if sim.Rewind(1) != 1 {
+		t.Fatalf("expected to rewind one generation")
+	}
+
+	for x := 1; x <= 3; x++ {
+		if !sim.Board().IsAlive(x, 2) {
+			t.Fatalf("expected the painted blinker at generation 0, got %v", sim.Board())
+		}
+	}
+}
+
+func TestShouldCountBoardReplacementsButNotEvolutionOrEdits(t *testing.T) {
+	sim := NewSimulation(8, 8, 4)
+	start := sim.Replaced()
+
+	sim.Tick()
+	sim.Tick()
+	sim.SetCell(0, 0, true)
+	sim.FastForward(1)
+	if sim.Replaced() != start {
+		t.Fatalf("expected ticks and edits to keep the board, got %d replacements", sim.Replaced()-start)
+	}
+
+	sim.Restart()
+	sim.Undo()
+	sim.Redo()
+	sim.Undo()
+	sim.Tick()
+	sim.Rewind(1)
+	if sim.Replaced()-start != 5 {
+		t.Fatalf("expected restart, undo, redo, undo and rewind to replace the board, got %d", sim.Replaced()-start)
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"math/rand"
+
+	"gol-on-cli/internal/engine"
+)
+
+type Region struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+func RegionFromCorners(x1, y1, x2, y2 int) Region {
+	if x2 < x1 {
+		x1, x2 = x2, x1
+	}
+	if y2 < y1 {
+		y1, y2 = y2, y1
+	}
+	return Region{X: x1, Y: y1, Width: x2 - x1 + 1, Height: y2 - y1 + 1}
+}
+
+func (r Region) Contains(x, y int) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.Width && y < r.Y+r.Height
+}
+
+func (s *Simulation) CopyRegion(region Region) engine.Board {
+	return s.board.Crop(region.X, region.Y, region.Width, region.Height)
+}
+
+func (s *Simulation) ClearRegion(region Region) {
+	s.editRegion(region, func(x, y int) bool { return false })
+}
+
+func (s *Simulation) FillRegion(region Region) {
+	s.editRegion(region, func(x, y int) bool { return true })
+}
+
+func (s *Simulation) RandomizeRegion(region Region, rng *rand.Rand) {
+	s.editRegion(region, func(x, y int) bool { return rng.Intn(2) == 1 })
+}
+
+func (s *Simulation) Paste(stamp engine.Board, x, y int) {
+	s.editRegion(Region{X: x, Y: y, Width: stamp.Width(), Height: stamp.Height()}, func(px, py int) bool {
+		return stamp.IsAlive(px-x, py-y)
+	})
+}
+
+func (s *Simulation) editRegion(region Region, cell func(x, y int) bool) {
+	edited := s.board.Clone()
+	for y := region.Y; y < region.Y+region.Height; y++ {
+		for x := region.X; x < region.X+region.Width; x++ {
+			edited.SetAlive(x, y, cell(x, y))
+		}
+	}
+	s.board = edited
+	s.stableGenerations = 0
+	s.replaceTimelineHead()
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"math/rand"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func emptySimulation(width, height int) *Simulation {
+	return NewSimulationWithFactory(width, height, func(w, h int) engine.Board {
+		return engine.NewBoard(w, h)
+	})
+}
+
+func TestShouldNormalizeRegionFromAnyCornerOrder(t *testing.T) {
+	region := RegionFromCorners(4, 5, 1, 2)
+
+	if region != (Region{X: 1, Y: 2, Width: 4, Height: 4}) {
+		t.Fatalf("unexpected normalized region %+v", region)
+	}
+}
+
+func TestShouldFillCopyAndClearRegion(t *testing.T) {
+	sim := emptySimulation(5, 5)
+	region := Region{X: 1, Y: 1, Width: 2, Height: 3}
+
+	sim.FillRegion(region)
+	copied := sim.CopyRegion(region)
+	sim.ClearRegion(region)
+
+	if copied.Population() != 6 {
+		t.Fatalf("expected 6 copied cells, got %d", copied.Population())
+	}
+	if sim.Board().Population() != 0 {
+		t.Fatalf("expected region cleared, got %d alive", sim.Board().Population())
+	}
+}
+
+func TestShouldPasteStampAtOffsetClippingToBoard(t *testing.T) {
+	sim := emptySimulation(4, 4)
+	stamp := engine.NewBoard(2, 2)
+	stamp.SetAlive(0, 0, true)
+	stamp.SetAlive(1, 1, true)
+
+	sim.Paste(stamp, 3, 3)
+
+	if !sim.Board().IsAlive(3, 3) || sim.Board().Population() != 1 {
+		t.Fatalf("expected clipped paste to place a single cell at (3,3)")
+	}
+}
+
+func TestShouldRandomizeOnlyInsideRegion(t *testing.T) {
+	sim := emptySimulation(10, 10)
+	region := Region{X: 2, Y: 2, Width: 4, Height: 4}
+
+	sim.RandomizeRegion(region, rand.New(rand.NewSource(3)))
+
+	board := sim.Board()
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			if board.IsAlive(x, y) && !region.Contains(x, y) {
+				t.Fatalf("expected randomized cells inside region, found (%d,%d)", x, y)
+			}
+		}
+	}
+	if board.Population() == 0 {
+		t.Fatalf("expected randomized region to contain live cells")
+	}
+}
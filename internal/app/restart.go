@@ -0,0 +1,152 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+const DefaultRestartLinger = 100
+
+// RestartState is what a RestartPolicy sees after each generation.
+type RestartState struct {
+	Generation       int
+	Board            engine.Board
+	Cycle            Cycle
+	Cycling          bool
+	CycleGenerations int
+}
+
+type RestartPolicy interface {
+	ShouldRestart(state RestartState) bool
+	String() string
+}
+
+type StillLifePolicy struct {
+	Linger int
+}
+
+func (p StillLifePolicy) ShouldRestart(state RestartState) bool {
+	return state.Cycling && state.Cycle.Period == 1 && !state.Cycle.Translated() && state.CycleGenerations >= p.Linger
+}
+
+func (p StillLifePolicy) String() string {
+	return "still"
+}
+
+// CyclePolicy restarts once any repetition (still life, oscillator or
+// spaceship) has lasted Linger generations.
+type CyclePolicy struct {
+	Linger int
+}
+
+func (p CyclePolicy) ShouldRestart(state RestartState) bool {
+	return state.Cycling && state.CycleGenerations >= p.Linger
+}
+
+func (p CyclePolicy) String() string {
+	return "cycle"
+}
+
+type PopulationPolicy struct {
+	Below int
+}
+
+func (p PopulationPolicy) ShouldRestart(state RestartState) bool {
+	return state.Board.Population() < p.Below
+}
+
+func (p PopulationPolicy) String() string {
+	return "population"
+}
+
+type MaxGenerationsPolicy struct {
+	Limit int
+}
+
+func (p MaxGenerationsPolicy) ShouldRestart(state RestartState) bool {
+	return state.Generation >= p.Limit
+}
+
+func (p MaxGenerationsPolicy) String() string {
+	return "generations"
+}
+
+type NeverRestart struct{}
+
+func (NeverRestart) ShouldRestart(RestartState) bool {
+	return false
+}
+
+func (NeverRestart) String() string {
+	return "never"
+}
+
+// AnyPolicy restarts as soon as one of its policies does.
+type AnyPolicy []RestartPolicy
+
+func (p AnyPolicy) ShouldRestart(state RestartState) bool {
+	for _, policy := range p {
+		if policy.ShouldRestart(state) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p AnyPolicy) String() string {
+	names := make([]string, len(p))
+	for i, policy := range p {
+		names[i] = policy.String()
+	}
+	return strings.Join(names, ",")
+}
+
+type RestartOptions struct {
+	Linger         int
+	MinPopulation  int
+	MaxGenerations int
+}
+
+func DefaultRestartPolicy() RestartPolicy {
+	return CyclePolicy{Linger: DefaultRestartLinger}
+}
+
+// ParseRestartPolicy builds a policy from a comma-separated list of
+// conditions: still, cycle (or oscillation), population, generations, never.
+func ParseRestartPolicy(spec string, options RestartOptions) (RestartPolicy, error) {
+	if options.Linger < 0 {
+		return nil, fmt.Errorf("invalid restart linger: must be zero or greater")
+	}
+	policies := make(AnyPolicy, 0)
+	for _, name := range strings.Split(spec, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "still":
+			policies = append(policies, StillLifePolicy{Linger: options.Linger})
+		case "cycle", "oscillation":
+			policies = append(policies, CyclePolicy{Linger: options.Linger})
+		case "population":
+			if options.MinPopulation <= 0 {
+				return nil, fmt.Errorf("restart on population needs a minimum population greater than zero")
+			}
+			policies = append(policies, PopulationPolicy{Below: options.MinPopulation})
+		case "generations":
+			if options.MaxGenerations <= 0 {
+				return nil, fmt.Errorf("restart on generations needs a maximum greater than zero")
+			}
+			policies = append(policies, MaxGenerationsPolicy{Limit: options.MaxGenerations})
+		case "never":
+			if len(strings.Split(spec, ",")) > 1 {
+				return nil, fmt.Errorf("restart policy never cannot be combined with other conditions")
+			}
+			return NeverRestart{}, nil
+		default:
+			return nil, fmt.Errorf("unknown restart condition %q (available: still, cycle, population, generations, never)", strings.TrimSpace(name))
+		}
+	}
+	if len(policies) == 1 {
+		return policies[0], nil
+	}
+	return policies, nil
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldParseCombinedRestartConditions(t *testing.T) {
+	policy, err := ParseRestartPolicy("still, population,generations", RestartOptions{Linger: 5, MinPopulation: 3, MaxGenerations: 50})
+	if err != nil {
+		t.Fatalf("expected policy to parse, got %v", err)
+	}
+	if policy.String() != "still,population,generations" {
+		t.Fatalf("unexpected policy %q", policy)
+	}
+
+	sparse := engine.NewBoard(4, 4)
+	sparse.SetAlive(1, 1, true)
+	if !policy.ShouldRestart(RestartState{Generation: 1, Board: sparse}) {
+		t.Fatalf("expected restart when population drops below threshold")
+	}
+	if !policy.ShouldRestart(RestartState{Generation: 50, Board: fullBoard(4, 4)}) {
+		t.Fatalf("expected restart at max generations")
+	}
+	oscillating := RestartState{Generation: 10, Board: fullBoard(4, 4), Cycle: Cycle{Period: 2}, Cycling: true, CycleGenerations: 9}
+	if policy.ShouldRestart(oscillating) {
+		t.Fatalf("expected still-life policy to ignore oscillators")
+	}
+}
+
+func TestShouldRejectInvalidRestartSpecs(t *testing.T) {
+	for _, spec := range []string{"sometimes", "never,still", "population"} {
+		if _, err := ParseRestartPolicy(spec, RestartOptions{Linger: 1}); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestShouldNeverRestartWhenPolicyIsNever(t *testing.T) {
+	sim := emptySimulation(6, 6)
+	sim.SetRestartPolicy(NeverRestart{})
+
+	for i := 0; i < 150; i++ {
+		sim.Tick()
+	}
+
+	if sim.Generation() != 150 || sim.AutoRestarts() != 0 {
+		t.Fatalf("expected empty board to keep running, got generation %d after %d restarts", sim.Generation(), sim.AutoRestarts())
+	}
+}
+
+func TestShouldCountPolicyRestarts(t *testing.T) {
+	sim := emptySimulation(6, 6)
+	sim.SetRestartPolicy(MaxGenerationsPolicy{Limit: 10})
+
+	for i := 0; i < 25; i++ {
+		sim.Tick()
+	}
+
+	if sim.AutoRestarts() != 2 || sim.Generation() != 5 {
+		t.Fatalf("expected two restarts ending at generation 5, got %d restarts at %d", sim.AutoRestarts(), sim.Generation())
+	}
+}
+
+func TestShouldKeepTheBoardOfTheFinishedSoup(t *testing.T) {
+	sim := NewSimulationFromSoupID(12, 12, "k_census#0", DefaultSoupSpec())
+	sim.SetRestartPolicy(MaxGenerationsPolicy{Limit: 3})
+	if _, _, ok := sim.FinishedSoup(); ok {
+		t.Fatalf("expected no finished soup before a restart")
+	}
+
+	sim.Tick()
+	sim.Tick()
+	last := sim.Board().NextGenerationWithRule(sim.Rule())
+	sim.Tick()
+
+	board, id, ok := sim.FinishedSoup()
+	if !ok || id != "k_census#0" || sim.SoupID() != "k_census#1" {
+		t.Fatalf("expected soup k_census#0 to finish, got %q %v (now %q)", id, ok, sim.SoupID())
+	}
+	if !boardsEqual(board, last) {
+		t.Fatalf("expected the finished board to be the soup's last generation")
+	}
+}
+
+func fullBoard(width, height int) engine.Board {
+	board := engine.NewBoard(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			board.SetAlive(x, y, true)
+		}
+	}
+	return board
+}
+
+func TestShouldKeepUndoAndRedoAcrossPolicyRestarts(t *testing.T) {
+	sim := emptySimulation(6, 6)
+	sim.Checkpoint()
+	sim.SetCell(1, 1, true)
+	sim.Undo()
+	sim.SetRestartPolicy(MaxGenerationsPolicy{Limit: 2})
+
+	sim.Tick()
+	sim.Tick()
+
+	if sim.AutoRestarts() != 1 || sim.CanUndo() || !sim.CanRedo() {
+		t.Fatalf("expected a policy restart to leave the undo history alone, got %d restarts, undo %v, redo %v", sim.AutoRestarts(), sim.CanUndo(), sim.CanRedo())
+	}
+}
+
+func TestShouldNoticePolicyThatHoldsBeforeTheFirstGeneration(t *testing.T) {
+	sim := NewSimulationFromSoupID(20, 20, "k_census#0", DefaultSoupSpec())
+
+	sim.SetRestartPolicy(PopulationPolicy{Below: 1000})
+	if !sim.RestartsAtOnce() {
+		t.Fatalf("expected a population threshold above the soup to hold at once")
+	}
+	sim.SetRestartPolicy(PopulationPolicy{Below: 1})
+	if sim.RestartsAtOnce() {
+		t.Fatalf("expected a low population threshold to wait")
+	}
+}
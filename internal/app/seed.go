@@ -0,0 +1,52 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+// soupSequence derives each soup from the SHA-256 of its ID alone.
+type soupSequence struct {
+	seed   string
+	next   int
+	spec   SoupSpec
+	lastID string
+}
+
+func newSoupSequence(seed string, first int, spec SoupSpec) *soupSequence {
+	return &soupSequence{seed: seed, next: first, spec: spec}
+}
+
+func (q *soupSequence) generate(width, height int) engine.Board {
+	q.lastID = SoupID(q.seed, q.next)
+	q.next++
+	return q.spec.Generate(SoupRand(q.lastID), width, height)
+}
+
+// SoupID names one soup as "<seed>#<index>".
+func SoupID(seed string, index int) string {
+	return fmt.Sprintf("%s#%d", seed, index)
+}
+
+func ParseSoupID(id string) (string, int, error) {
+	cut := strings.LastIndex(id, "#")
+	if cut <= 0 {
+		return "", 0, fmt.Errorf("invalid soup id %q: expected <seed>#<index>", id)
+	}
+	index, err := strconv.Atoi(id[cut+1:])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid soup id %q: index must be a non-negative integer", id)
+	}
+	return id[:cut], index, nil
+}
+
+func SoupRand(id string) *rand.Rand {
+	sum := sha256.Sum256([]byte(id))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+}
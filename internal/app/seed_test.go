@@ -0,0 +1,56 @@
+package app
+
+import "testing"
+
+func TestShouldDeriveEachSoupFromSeedAndRestartIndex(t *testing.T) {
+	sim := NewSimulation(30, 30, 5)
+	sim.Restart()
+	sim.Restart()
+
+	direct := NewSimulationFromSoupID(30, 30, "5#2", DefaultSoupSpec())
+
+	if sim.SoupID() != "5#2" || !boardsEqual(sim.Board(), direct.Board()) {
+		t.Fatalf("expected third soup to match soup 5#2, got %s", sim.SoupID())
+	}
+}
+
+func TestShouldNotDependOnPriorEditsOrUndo(t *testing.T) {
+	sim := NewSimulation(30, 30, 9)
+	sim.Restart()
+	sim.Undo()
+	if sim.SoupID() != "9#0" {
+		t.Fatalf("expected undo to restore the first soup id, got %s", sim.SoupID())
+	}
+	sim.Restart()
+
+	if sim.SoupID() != "9#2" {
+		t.Fatalf("expected restart index to keep counting, got %s", sim.SoupID())
+	}
+	if !boardsEqual(sim.Board(), NewSimulationFromSoupID(30, 30, "9#2", DefaultSoupSpec()).Board()) {
+		t.Fatalf("expected soup 9#2 to be reproducible")
+	}
+}
+
+func TestShouldTreatIntegerAndStringSeedsAlike(t *testing.T) {
+	numeric := NewSimulation(20, 20, 42)
+	text := NewSimulationFromSoupID(20, 20, "42", DefaultSoupSpec())
+	catagolue := NewSimulationFromSoupID(20, 20, "k_abc123", DefaultSoupSpec())
+
+	if !boardsEqual(numeric.Board(), text.Board()) {
+		t.Fatalf("expected --seed 42 and --seed-string 42 to match")
+	}
+	if catagolue.SoupID() != "k_abc123#0" || boardsEqual(catagolue.Board(), text.Board()) {
+		t.Fatalf("expected a distinct soup for k_abc123, got %s", catagolue.SoupID())
+	}
+}
+
+func TestShouldRejectMalformedSoupIDs(t *testing.T) {
+	for _, id := range []string{"abc", "#3", "abc#-1", "abc#x"} {
+		if _, _, err := ParseSoupID(id); err == nil {
+			t.Fatalf("expected %q to be rejected", id)
+		}
+	}
+	if seed, index, err := ParseSoupID("k_a#b#7"); err != nil || seed != "k_a#b" || index != 7 {
+		t.Fatalf("expected last # to split, got %q %d %v", seed, index, err)
+	}
+}
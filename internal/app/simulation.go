@@ -1,12 +1,16 @@
 package app
 
 import (
-	"math/rand"
+	"fmt"
+	"strconv"
 
 	"gol-on-cli/internal/engine"
 	"gol-on-cli/internal/pattern"
 )
 
+// MaxSeekDistance bounds how many generations Seek computes in one call.
+const MaxSeekDistance = 10000
+
 type BoardFactory func(width, height int) engine.Board
 
 type Simulation struct {
@@ -17,18 +21,50 @@ type Simulation struct {
 	width             int
 	height            int
 	boardFactory      BoardFactory
+	history           undoHistory
+	timeline          *timeline
+	timelineCursor    int
+	rule              engine.Rule
+	cycles            *cycleDetector
+	cycle             Cycle
+	cycling           bool
+	restartPolicy     RestartPolicy
+	autoRestarts      int
+	replaced          int
+	soup              SoupSpec
+	soups             *soupSequence
+	soupID            string
+	finishedSoup      engine.Board
+	finishedSoupID    string
+	finished          bool
+	ownsBoard         bool
+	editsPending      bool
 }
 
 func NewSimulation(width, height int, seed int64) *Simulation {
-	rng := rand.New(rand.NewSource(seed))
-	factory := func(w, h int) engine.Board {
-		return randomBoard(rng, w, h)
+	return NewSimulationWithSoup(width, height, seed, DefaultSoupSpec())
+}
+
+func NewSimulationWithSoup(width, height int, seed int64, soup SoupSpec) *Simulation {
+	return NewSimulationFromSoupID(width, height, SoupID(strconv.FormatInt(seed, 10), 0), soup)
+}
+
+// NewSimulationFromSoupID starts at the soup named by id ("<seed>#<index>").
+func NewSimulationFromSoupID(width, height int, id string, soup SoupSpec) *Simulation {
+	seed, index, err := ParseSoupID(id)
+	if err != nil {
+		seed, index = id, 0
 	}
-	return NewSimulationWithFactory(width, height, factory)
+	soups := newSoupSequence(seed, index, soup)
+	sim := NewSimulationWithFactory(width, height, soups.generate)
+	sim.soup = soup
+	sim.soups = soups
+	sim.soupID = soups.lastID
+	return sim
 }
 
 func NewSimulationWithFactory(width, height int, factory BoardFactory) *Simulation {
-	return &Simulation{
+	sim := &Simulation{
 		board:             factory(width, height),
 		generation:        0,
 		stableGenerations: 0,
@@ -36,26 +72,81 @@ func NewSimulationWithFactory(width, height int, factory BoardFactory) *Simulati
 		width:             width,
 		height:            height,
 		boardFactory:      factory,
+		timeline:          newTimeline(defaultTimelineCapacity, timelineKeyframeEvery),
+		rule:              engine.ConwayRule(),
+		cycles:            newCycleDetector(cycleWindow),
+		restartPolicy:     DefaultRestartPolicy(),
+		soup:              DefaultSoupSpec(),
 	}
+	sim.resetTimeline()
+	return sim
 }
 
 func (s *Simulation) Tick() {
 	if s.paused {
 		return
 	}
-	next := s.board.NextGeneration()
-	if boardsMatch(s.board, next) {
+	s.flushEdits()
+	next := s.board.NextGenerationWithRule(s.rule)
+	s.cycle, s.cycling = s.cycles.observe(next, s.generation+1)
+	if s.cycling {
 		s.stableGenerations++
 	} else {
 		s.stableGenerations = 0
 	}
-	if s.stableGenerations >= 100 {
-		s.Restart()
-		s.stableGenerations = 0
+	if s.restartPolicy.ShouldRestart(RestartState{
+		Generation:       s.generation + 1,
+		Board:            next,
+		Cycle:            s.cycle,
+		Cycling:          s.cycling,
+		CycleGenerations: s.stableGenerations,
+	}) {
+		s.finishedSoup, s.finishedSoupID, s.finished = next, s.soupID, true
+		s.nextSoup()
+		s.autoRestarts++
 		return
 	}
 	s.board = next
 	s.generation++
+	s.recordTimeline()
+}
+
+func (s *Simulation) Step() {
+	paused := s.paused
+	s.paused = false
+	s.Tick()
+	s.paused = paused
+}
+
+func (s *Simulation) SetCell(x, y int, alive bool) {
+	if x < 0 || y < 0 || x >= s.board.Width() || y >= s.board.Height() {
+		return
+	}
+	if s.board.IsAlive(x, y) == alive {
+		return
+	}
+	if !s.ownsBoard {
+		s.board = s.board.Clone()
+		s.ownsBoard = true
+	}
+	s.board.SetAlive(x, y, alive)
+	s.stableGenerations = 0
+	s.cycle, s.cycling = Cycle{}, false
+	s.editsPending = true
+}
+
+func (s *Simulation) flushEdits() {
+	if s.editsPending {
+		s.replaceTimelineHead()
+	}
+}
+
+func (s *Simulation) ToggleCell(x, y int) {
+	s.SetCell(x, y, !s.board.IsAlive(x, y))
+}
+
+func (s *Simulation) Paused() bool {
+	return s.paused
 }
 
 func (s *Simulation) Pause() {
@@ -67,9 +158,21 @@ func (s *Simulation) Resume() {
 }
 
 func (s *Simulation) Restart() {
+	s.Checkpoint()
+	s.nextSoup()
+}
+
+// nextSoup is Restart without the undo checkpoint.
+func (s *Simulation) nextSoup() {
 	s.board = s.boardFactory(s.width, s.height)
+	s.soupID = ""
+	if s.soups != nil {
+		s.soupID = s.soups.lastID
+	}
 	s.generation = 0
 	s.stableGenerations = 0
+	s.replaced++
+	s.resetTimeline()
 }
 
 func (s *Simulation) LoadPatternFromWikiContent(content string) error {
@@ -78,12 +181,98 @@ func (s *Simulation) LoadPatternFromWikiContent(content string) error {
 		return err
 	}
 
+	s.Checkpoint()
 	s.board = parsedBoard
+	s.soupID = ""
 	s.generation = 0
 	s.stableGenerations = 0
+	s.replaced++
+	s.resetTimeline()
 	return nil
 }
 
+func (s *Simulation) Rule() engine.Rule {
+	return s.rule
+}
+
+func (s *Simulation) SetRule(rule engine.Rule) {
+	s.rule = rule
+	s.stableGenerations = 0
+	s.resetTimeline()
+}
+
+func (s *Simulation) Reseed(seed int64) {
+	s.ReseedString(strconv.FormatInt(seed, 10))
+}
+
+// ReseedString restarts at soup 0 of a string seed, as with --seed-string.
+func (s *Simulation) ReseedString(seed string) {
+	s.soups = newSoupSequence(seed, 0, s.soup)
+	s.boardFactory = s.soups.generate
+	s.Restart()
+}
+
+// SoupID names the random soup on the board, or "" once a pattern was
+// loaded or for custom board factories.
+func (s *Simulation) SoupID() string {
+	return s.soupID
+}
+
+func (s *Simulation) Seek(target int) error {
+	if target < 0 {
+		return fmt.Errorf("invalid generation: must be zero or greater")
+	}
+	if target < s.generation {
+		position, _ := s.TimelinePosition()
+		if s.generation-target > position {
+			return fmt.Errorf("generation %d is no longer in the timeline", target)
+		}
+		s.Rewind(s.generation - target)
+		return nil
+	}
+	if target-s.generation > MaxSeekDistance {
+		return fmt.Errorf("generation %d is more than %d generations ahead", target, MaxSeekDistance)
+	}
+	for s.generation < target {
+		before := s.generation
+		if s.FastForward(1) == 0 {
+			s.Step()
+		}
+		if s.generation <= before {
+			return fmt.Errorf("simulation restarted at generation %d before reaching %d", before, target)
+		}
+	}
+	return nil
+}
+
+func (s *Simulation) SetRestartPolicy(policy RestartPolicy) {
+	s.restartPolicy = policy
+}
+
+func (s *Simulation) RestartPolicy() RestartPolicy {
+	return s.restartPolicy
+}
+
+// RestartsAtOnce reports whether the restart policy holds before the first
+// generation.
+func (s *Simulation) RestartsAtOnce() bool {
+	return s.restartPolicy.ShouldRestart(RestartState{Generation: s.generation, Board: s.board})
+}
+
+func (s *Simulation) AutoRestarts() int {
+	return s.autoRestarts
+}
+
+// FinishedSoup is the last board and ID of the soup the restart policy
+// ended most recently.
+func (s *Simulation) FinishedSoup() (engine.Board, string, bool) {
+	return s.finishedSoup, s.finishedSoupID, s.finished
+}
+
+func (s *Simulation) Cycle() (Cycle, bool) {
+	return s.cycle, s.cycling
+}
+
 func (s *Simulation) Generation() int {
 	return s.generation
 }
@@ -92,7 +281,16 @@ func (s *Simulation) Board() engine.Board {
 	return s.board
 }
 
+// Replaced counts restarts, loads, resizes, undo, redo and rewinds.
+func (s *Simulation) Replaced() int {
+	return s.replaced
+}
+
 func (s *Simulation) Resize(width, height int) {
+	if width == s.width && height == s.height && s.board.Width() == width && s.board.Height() == height {
+		return
+	}
+	s.Checkpoint()
 	resized := engine.NewBoard(width, height)
 	copyWidth := min(s.board.Width(), width)
 	copyHeight := min(s.board.Height(), height)
@@ -107,6 +305,8 @@ func (s *Simulation) Resize(width, height int) {
 	s.width = width
 	s.height = height
 	s.stableGenerations = 0
+	s.replaced++
+	s.resetTimeline()
 }
 
 func min(left, right int) int {
@@ -115,52 +315,3 @@ func min(left, right int) int {
 	}
 	return right
 }
-func randomBoard(rng *rand.Rand, width, height int) engine.Board {
-	board := engine.NewBoard(width, height)
-	if width <= 0 || height <= 0 {
-		return board
-	}
-
-	const startupWindowSize = 20
-	windowW := width
-	if windowW > startupWindowSize {
-		windowW = startupWindowSize
-	}
-	windowH := height
-	if windowH > startupWindowSize {
-		windowH = startupWindowSize
-	}
-
-	startX := (width - windowW) / 2
-	startY := (height - windowH) / 2
-
-	maxCells := windowW * windowH
-	target := (maxCells + 1) / 2
-
-	picked := make(map[int]struct{}, target)
-	for len(picked) < target {
-		index := rng.Intn(maxCells)
-		if _, exists := picked[index]; exists {
-			continue
-		}
-		picked[index] = struct{}{}
-		x := index % windowW
-		y := index / windowW
-		board.SetAlive(startX+x, startY+y, true)
-	}
-	return board
-}
-
-func boardsMatch(left, right engine.Board) bool {
-	if left.Width() != right.Width() || left.Height() != right.Height() {
-		return false
-	}
-	for y := 0; y < left.Height(); y++ {
-		for x := 0; x < left.Width(); x++ {
-			if left.IsAlive(x, y) != right.IsAlive(x, y) {
-				return false
-			}
-		}
-	}
-	return true
-}
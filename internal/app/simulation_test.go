@@ -174,3 +174,82 @@ func TestShouldSeedHalfDensityWithinDoubleStartupWindow(t *testing.T) {
 		t.Fatalf("expected 50%% density in 20x20 startup window (200 alive cells), got %d", aliveCount)
 	}
 }
+
+func TestShouldToggleCellWithoutMutatingPreviousBoardSnapshot(t *testing.T) {
+	sim := NewSimulationWithFactory(3, 3, func(width, height int) engine.Board {
+		return engine.NewBoard(width, height)
+	})
+	before := sim.Board()
+
+	sim.ToggleCell(1, 1)
+
+	if !sim.Board().IsAlive(1, 1) {
+		t.Fatalf("expected toggled cell to be alive")
+	}
+	if before.IsAlive(1, 1) {
+		t.Fatalf("expected previous board snapshot to remain unchanged")
+	}
+	sim.SetCell(1, 1, false)
+	if sim.Board().IsAlive(1, 1) {
+		t.Fatalf("expected erased cell to be dead")
+	}
+}
+
+func TestShouldAdvanceOneGenerationWithStepWhilePaused(t *testing.T) {
+	sim := NewSimulation(5, 5, 1)
+	sim.Pause()
+
+	sim.Step()
+
+	if sim.Generation() != 1 {
+		t.Fatalf("expected single step to advance generation while paused, got %d", sim.Generation())
+	}
+	if !sim.Paused() {
+		t.Fatalf("expected simulation to remain paused after step")
+	}
+}
+
+func TestShouldEvolveWithConfiguredRule(t *testing.T) {
+	rule, _ := engine.ParseRule("B36/S23")
+	sim := emptySimulation(5, 5)
+	for _, p := range [][2]int{{1, 1}, {2, 1}, {3, 1}, {1, 3}, {2, 3}, {3, 3}} {
+		sim.SetCell(p[0], p[1], true)
+	}
+
+	sim.SetRule(rule)
+	sim.Tick()
+
+	if !sim.Board().IsAlive(2, 2) {
+		t.Fatalf("expected HighLife birth after switching rule")
+	}
+	if sim.Rule().String() != "B36/S23" {
+		t.Fatalf("expected rule to be reported, got %s", sim.Rule())
+	}
+}
+
+func TestShouldReseedToSameSoupAsFreshSimulation(t *testing.T) {
+	sim := NewSimulation(10, 10, 1)
+
+	sim.Reseed(42)
+
+	if !boardsEqual(sim.Board(), NewSimulation(10, 10, 42).Board()) {
+		t.Fatalf("expected reseeded soup to match a fresh simulation with the same seed")
+	}
+}
+
+func TestShouldSeekForwardAndBackToGeneration(t *testing.T) {
+	sim := NewSimulation(12, 12, 3)
+
+	if err := sim.Seek(15); err != nil || sim.Generation() != 15 {
+		t.Fatalf("expected seek forward to 15, got %d (%v)", sim.Generation(), err)
+	}
+	if err := sim.Seek(4); err != nil || sim.Generation() != 4 {
+		t.Fatalf("expected seek back to 4, got %d (%v)", sim.Generation(), err)
+	}
+	if err := sim.Seek(-1); err == nil {
+		t.Fatalf("expected error for negative generation")
+	}
+	if err := sim.Seek(4 + MaxSeekDistance + 1); err == nil || sim.Generation() != 4 {
+		t.Fatalf("expected a seek past the limit to be refused, got %d (%v)", sim.Generation(), err)
+	}
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+type Placement string
+
+const (
+	PlacementCenter Placement = "center"
+	PlacementRandom Placement = "random"
+)
+
+const defaultSoupWindow = 20
+
+// SoupSpec describes how random starting boards are drawn: the fraction of
+// live cells, the size of the seeded window (zero means the whole board), where
+// the window sits, and the symmetry imposed on it.
+type SoupSpec struct {
+	Density   float64
+	Width     int
+	Height    int
+	Placement Placement
+	Symmetry  Symmetry
+}
+
+func DefaultSoupSpec() SoupSpec {
+	return SoupSpec{
+		Density:   0.5,
+		Width:     defaultSoupWindow,
+		Height:    defaultSoupWindow,
+		Placement: PlacementCenter,
+		Symmetry:  SymmetryC1,
+	}
+}
+
+func (spec SoupSpec) Validate() error {
+	if spec.Density < 0 || spec.Density > 1 {
+		return fmt.Errorf("invalid soup density: must be between 0 and 1")
+	}
+	if spec.Width < 0 || spec.Height < 0 {
+		return fmt.Errorf("invalid soup size: must be zero (full board) or greater")
+	}
+	if spec.Placement != PlacementCenter && spec.Placement != PlacementRandom {
+		return fmt.Errorf("invalid soup placement %q (available: center, random)", spec.Placement)
+	}
+	if _, ok := symmetries[spec.Symmetry]; !ok {
+		return fmt.Errorf("unknown soup symmetry %q (available: %s)", spec.Symmetry, strings.Join(SymmetryNames(), ", "))
+	}
+	return nil
+}
+
+// ParseSoupSize accepts "WxH", a single number for a square window, or
+// "full" for the whole board.
+func ParseSoupSize(value string) (int, int, error) {
+	if strings.EqualFold(value, "full") {
+		return 0, 0, nil
+	}
+	w, h, found := strings.Cut(strings.ToLower(value), "x")
+	if !found {
+		h = w
+	}
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid soup size %q: use WxH, N or full", value)
+	}
+	return width, height, nil
+}
+
+// Size is the window in the form ParseSoupSize reads.
+func (spec SoupSpec) Size() string {
+	if spec.Width == 0 && spec.Height == 0 {
+		return "full"
+	}
+	return fmt.Sprintf("%dx%d", spec.Width, spec.Height)
+}
+
+func (spec SoupSpec) Generate(rng *rand.Rand, width, height int) engine.Board {
+	board := engine.NewBoard(width, height)
+	if width <= 0 || height <= 0 {
+		return board
+	}
+	group := symmetries[spec.Symmetry]
+
+	windowW, windowH := spec.Width, spec.Height
+	if windowW == 0 || windowW > width {
+		windowW = width
+	}
+	if windowH == 0 || windowH > height {
+		windowH = height
+	}
+	if group.square {
+		windowW = min(windowW, windowH)
+		windowH = windowW
+	}
+	windowW = withParity(windowW, group.oddWidth, group.evenWidth)
+	windowH = withParity(windowH, group.oddHeight, group.evenHeight)
+	if windowW <= 0 || windowH <= 0 {
+		return board
+	}
+
+	startX := (width - windowW) / 2
+	startY := (height - windowH) / 2
+	if spec.Placement == PlacementRandom {
+		startX = rng.Intn(width - windowW + 1)
+		startY = rng.Intn(height - windowH + 1)
+	}
+
+	if len(group.generators) == 0 {
+		fillExact(board, rng, startX, startY, windowW, windowH, spec.Density)
+		return board
+	}
+	decided := make([]bool, windowW*windowH)
+	for y := 0; y < windowH; y++ {
+		for x := 0; x < windowW; x++ {
+			if decided[y*windowW+x] {
+				continue
+			}
+			alive := rng.Float64() < spec.Density
+			for _, p := range orbit(group.generators, x, y, windowW, windowH) {
+				decided[p[1]*windowW+p[0]] = true
+				if alive {
+					board.SetAlive(startX+p[0], startY+p[1], true)
+				}
+			}
+		}
+	}
+	return board
+}
+
+// fillExact picks exactly density*cells distinct cells.
+func fillExact(board engine.Board, rng *rand.Rand, startX, startY, windowW, windowH int, density float64) {
+	maxCells := windowW * windowH
+	target := int(math.Ceil(density * float64(maxCells)))
+	picked := make(map[int]struct{}, target)
+	for len(picked) < target {
+		index := rng.Intn(maxCells)
+		if _, exists := picked[index]; exists {
+			continue
+		}
+		picked[index] = struct{}{}
+		board.SetAlive(startX+index%windowW, startY+index/windowW, true)
+	}
+}
+
+func withParity(size int, odd, even bool) int {
+	switch {
+	case odd && size%2 == 0:
+		return size - 1
+	case even && size%2 == 1:
+		return size - 1
+	}
+	return size
+}
+
+type cellMap func(x, y, w, h int) (int, int)
+
+var (
+	rotate180 cellMap = func(x, y, w, h int) (int, int) { return w - 1 - x, h - 1 - y }
+	rotate90  cellMap = func(x, y, w, h int) (int, int) { return h - 1 - y, x }
+	mirrorX   cellMap = func(x, y, w, h int) (int, int) { return w - 1 - x, y }
+	mirrorY   cellMap = func(x, y, w, h int) (int, int) { return x, h - 1 - y }
+	transpose cellMap = func(x, y, w, h int) (int, int) { return y, x }
+	antiDiag  cellMap = func(x, y, w, h int) (int, int) { return w - 1 - y, h - 1 - x }
+)
+
+func orbit(generators []cellMap, x, y, w, h int) [][2]int {
+	seen := map[[2]int]bool{{x, y}: true}
+	queue := [][2]int{{x, y}}
+	for i := 0; i < len(queue); i++ {
+		for _, g := range generators {
+			nx, ny := g(queue[i][0], queue[i][1], w, h)
+			p := [2]int{nx, ny}
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return queue
+}
+
+// Symmetry names follow Catagolue: the group (C1, C2, C4, D2, D4, D8) and
+// where the centre lies (_1 on a cell, _2 on an edge, _4 on a corner; +
+// for orthogonal mirrors, x for diagonal ones).
+type Symmetry string
+
+const SymmetryC1 Symmetry = "C1"
+
+type symmetryGroup struct {
+	generators []cellMap
+	square     bool
+	oddWidth   bool
+	oddHeight  bool
+	evenWidth  bool
+	evenHeight bool
+}
+
+var symmetries = map[Symmetry]symmetryGroup{
+	"C1":    {},
+	"C2_1":  {generators: []cellMap{rotate180}, oddWidth: true, oddHeight: true},
+	"C2_2":  {generators: []cellMap{rotate180}, oddWidth: true, evenHeight: true},
+	"C2_4":  {generators: []cellMap{rotate180}, evenWidth: true, evenHeight: true},
+	"C4_1":  {generators: []cellMap{rotate90}, square: true, oddWidth: true, oddHeight: true},
+	"C4_4":  {generators: []cellMap{rotate90}, square: true, evenWidth: true, evenHeight: true},
+	"D2_+1": {generators: []cellMap{mirrorY}, oddHeight: true},
+	"D2_+2": {generators: []cellMap{mirrorY}, evenHeight: true},
+	"D2_x":  {generators: []cellMap{transpose}, square: true},
+	"D4_+1": {generators: []cellMap{mirrorX, mirrorY}, oddWidth: true, oddHeight: true},
+	"D4_+2": {generators: []cellMap{mirrorX, mirrorY}, oddWidth: true, evenHeight: true},
+	"D4_+4": {generators: []cellMap{mirrorX, mirrorY}, evenWidth: true, evenHeight: true},
+	"D4_x1": {generators: []cellMap{transpose, antiDiag}, square: true, oddWidth: true, oddHeight: true},
+	"D4_x4": {generators: []cellMap{transpose, antiDiag}, square: true, evenWidth: true, evenHeight: true},
+	"D8_1":  {generators: []cellMap{rotate90, transpose}, square: true, oddWidth: true, oddHeight: true},
+	"D8_4":  {generators: []cellMap{rotate90, transpose}, square: true, evenWidth: true, evenHeight: true},
+}
+
+func SymmetryNames() []string {
+	names := make([]string, 0, len(symmetries))
+	for name := range symmetries {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSymmetry matches names case-insensitively.
+func ParseSymmetry(value string) (Symmetry, error) {
+	for name := range symmetries {
+		if strings.EqualFold(string(name), value) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown soup symmetry %q (available: %s)", value, strings.Join(SymmetryNames(), ", "))
+}
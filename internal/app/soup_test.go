@@ -0,0 +1,82 @@
+package app
+
+import (
+	"math/rand"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldFillWholeBoardAtRequestedDensity(t *testing.T) {
+	spec := SoupSpec{Density: 0.25, Placement: PlacementCenter, Symmetry: SymmetryC1}
+
+	board := spec.Generate(rand.New(rand.NewSource(1)), 20, 10)
+
+	if board.Population() != 50 {
+		t.Fatalf("expected exactly a quarter of 200 cells alive, got %d", board.Population())
+	}
+}
+
+func TestShouldKeepRandomPlacementInsideBoard(t *testing.T) {
+	spec := SoupSpec{Density: 1, Width: 4, Height: 3, Placement: PlacementRandom, Symmetry: SymmetryC1}
+
+	for seed := int64(0); seed < 20; seed++ {
+		board := spec.Generate(rand.New(rand.NewSource(seed)), 12, 9)
+		if _, _, w, h := board.BoundingBox(); w != 4 || h != 3 || board.Population() != 12 {
+			t.Fatalf("expected a full 4x3 window, got %dx%d with %d cells", w, h, board.Population())
+		}
+	}
+}
+
+func TestShouldApplyCatagolueSymmetries(t *testing.T) {
+	cases := map[string]func(board engine.Board, x, y, w, h int) bool{
+		"C2_4":  func(b engine.Board, x, y, w, h int) bool { return b.IsAlive(x, y) == b.IsAlive(w-1-x, h-1-y) },
+		"C4_1":  func(b engine.Board, x, y, w, h int) bool { return b.IsAlive(x, y) == b.IsAlive(h-1-y, x) },
+		"D2_+1": func(b engine.Board, x, y, w, h int) bool { return b.IsAlive(x, y) == b.IsAlive(x, h-1-y) },
+		"D8_1": func(b engine.Board, x, y, w, h int) bool {
+			return b.IsAlive(x, y) == b.IsAlive(y, x) && b.IsAlive(x, y) == b.IsAlive(w-1-x, y)
+		},
+	}
+	for name, symmetric := range cases {
+		symmetry, err := ParseSymmetry(name)
+		if err != nil {
+			t.Fatalf("expected %s to parse, got %v", name, err)
+		}
+		spec := SoupSpec{Density: 0.5, Width: 16, Height: 16, Placement: PlacementCenter, Symmetry: symmetry}
+		soup := spec.Generate(rand.New(rand.NewSource(7)), 16, 16)
+		// odd-centred axes shrink that side to 15, placing the window at the origin
+		w, h := 16, 16
+		if symmetries[symmetry].oddWidth {
+			w = 15
+		}
+		if symmetries[symmetry].oddHeight {
+			h = 15
+		}
+		window := soup.Crop(0, 0, w, h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				if !symmetric(window, x, y, w, h) {
+					t.Fatalf("expected %s symmetry at %d,%d", name, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestShouldParseSoupSizeAndRejectBadSpecs(t *testing.T) {
+	if w, h, err := ParseSoupSize("32x16"); err != nil || w != 32 || h != 16 {
+		t.Fatalf("expected 32x16, got %dx%d (%v)", w, h, err)
+	}
+	if w, h, err := ParseSoupSize("full"); err != nil || w != 0 || h != 0 {
+		t.Fatalf("expected full board, got %dx%d (%v)", w, h, err)
+	}
+	if _, _, err := ParseSoupSize("0x5"); err == nil {
+		t.Fatalf("expected zero size to be rejected")
+	}
+	if err := (SoupSpec{Density: 1.5, Placement: PlacementCenter, Symmetry: SymmetryC1}).Validate(); err == nil {
+		t.Fatalf("expected density above 1 to be rejected")
+	}
+	if _, err := ParseSymmetry("C3"); err == nil {
+		t.Fatalf("expected unknown symmetry to be rejected")
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+
+	"gol-on-cli/internal/engine"
+)
+
+const DefaultStatsHistory = 512
+
+type Stats struct {
+	Generation int
+	Population int
+	Births     int
+	Deaths     int
+	Density    float64
+	BoxX       int
+	BoxY       int
+	BoxWidth   int
+	BoxHeight  int
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("gen=%d pop=%d births=%d deaths=%d density=%.4f box=%dx%d@%d,%d",
+		s.Generation, s.Population, s.Births, s.Deaths, s.Density, s.BoxWidth, s.BoxHeight, s.BoxX, s.BoxY)
+}
+
+// StatsCollector derives per-generation statistics from successive boards and
+// keeps a bounded population history for graphing.
+type StatsCollector struct {
+	capacity    int
+	history     []int
+	current     Stats
+	last        engine.Board
+	baseline    engine.Board
+	hasBaseline bool
+	observed    bool
+}
+
+func NewStatsCollector(capacity int) *StatsCollector {
+	return &StatsCollector{capacity: capacity}
+}
+
+// Observe records the board for a generation. Observing the same generation
+// again (after an edit, say) replaces the latest sample instead of adding one.
+func (c *StatsCollector) Observe(board engine.Board, generation int) Stats {
+	replace := c.observed && generation == c.current.Generation
+	if !replace {
+		c.baseline, c.hasBaseline = c.last, c.observed
+	}
+	stats := Stats{Generation: generation}
+	sameSize := c.hasBaseline && c.baseline.Width() == board.Width() && c.baseline.Height() == board.Height()
+	for y := 0; y < board.Height(); y++ {
+		for x := 0; x < board.Width(); x++ {
+			alive := board.IsAlive(x, y)
+			if alive {
+				stats.Population++
+			}
+			if !sameSize {
+				continue
+			}
+			wasAlive := c.baseline.IsAlive(x, y)
+			if alive && !wasAlive {
+				stats.Births++
+			} else if !alive && wasAlive {
+				stats.Deaths++
+			}
+		}
+	}
+	if cells := board.Width() * board.Height(); cells > 0 {
+		stats.Density = float64(stats.Population) / float64(cells)
+	}
+	stats.BoxX, stats.BoxY, stats.BoxWidth, stats.BoxHeight = board.BoundingBox()
+
+	if replace && len(c.history) > 0 {
+		c.history[len(c.history)-1] = stats.Population
+	} else {
+		c.history = append(c.history, stats.Population)
+	}
+	if len(c.history) > c.capacity {
+		c.history = c.history[len(c.history)-c.capacity:]
+	}
+	c.current = stats
+	c.last = board
+	c.observed = true
+	return stats
+}
+
+func (c *StatsCollector) Current() Stats {
+	return c.current
+}
+
+func (c *StatsCollector) History() []int {
+	return c.history
+}
+
+func (c *StatsCollector) Reset() {
+	c.history = nil
+	c.current = Stats{}
+	c.observed = false
+	c.hasBaseline = false
+}
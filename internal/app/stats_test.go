@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldCountBirthsAndDeathsBetweenObservations(t *testing.T) {
+	collector := NewStatsCollector(10)
+	blinker := engine.NewBoard(5, 5)
+	for x := 1; x <= 3; x++ {
+		blinker.SetAlive(x, 2, true)
+	}
+
+	first := collector.Observe(blinker, 0)
+	second := collector.Observe(blinker.NextGeneration(), 1)
+
+	if first.Births != 0 || first.Population != 3 {
+		t.Fatalf("expected no births on first observation, got %+v", first)
+	}
+	if second.Births != 2 || second.Deaths != 2 || second.Population != 3 {
+		t.Fatalf("expected blinker to swap two cells, got %+v", second)
+	}
+	if second.BoxWidth != 1 || second.BoxHeight != 3 || second.Density != 3.0/25 {
+		t.Fatalf("unexpected box or density: %+v", second)
+	}
+}
+
+func TestShouldBoundPopulationHistory(t *testing.T) {
+	collector := NewStatsCollector(3)
+	board := engine.NewBoard(4, 4)
+
+	for i := 0; i < 5; i++ {
+		board = board.Clone()
+		board.SetAlive(i%4, i/4, true)
+		collector.Observe(board, i)
+	}
+
+	history := collector.History()
+	if len(history) != 3 || history[0] != 3 || history[2] != 5 {
+		t.Fatalf("expected last three populations, got %v", history)
+	}
+}
+
+func TestShouldReplaceSampleWhenSameGenerationIsObservedAgain(t *testing.T) {
+	collector := NewStatsCollector(10)
+	board := engine.NewBoard(4, 4)
+	collector.Observe(board, 0)
+	next := board.Clone()
+	next.SetAlive(1, 1, true)
+	collector.Observe(next, 1)
+
+	edited := next.Clone()
+	edited.SetAlive(2, 2, true)
+	stats := collector.Observe(edited, 1)
+
+	if len(collector.History()) != 2 || stats.Population != 2 || stats.Births != 2 {
+		t.Fatalf("expected edit to replace the generation 1 sample, got %v %+v", collector.History(), stats)
+	}
+}
+
+func TestShouldStartFreshHistoryAfterResetToGenerationZero(t *testing.T) {
+	collector := NewStatsCollector(10)
+	blinker := engine.NewBoard(5, 5)
+	for x := 1; x <= 3; x++ {
+		blinker.SetAlive(x, 2, true)
+	}
+	collector.Observe(blinker, 0)
+	collector.Observe(blinker.NextGeneration(), 1)
+	collector.Observe(blinker, 2)
+
+	collector.Reset()
+	block := engine.NewBoard(5, 5)
+	block.SetAlive(0, 0, true)
+	block.SetAlive(1, 0, true)
+	stats := collector.Observe(block, 0)
+
+	if stats.Generation != 0 || stats.Births != 0 || stats.Deaths != 0 || stats.Population != 2 {
+		t.Fatalf("expected the new board not to be diffed against the old one, got %+v", stats)
+	}
+	if history := collector.History(); len(history) != 1 || history[0] != 2 {
+		t.Fatalf("expected history to restart at generation 0, got %v", history)
+	}
+}
@@ -0,0 +1,215 @@
+package app
+
+import "gol-on-cli/internal/engine"
+
+const (
+	defaultTimelineCapacity = 1000
+	timelineKeyframeEvery   = 32
+)
+
+type timelineGroup struct {
+	keyframe    []uint64
+	deltas      [][]int32
+	generations []int
+}
+
+func (g timelineGroup) frames() int {
+	return len(g.generations)
+}
+
+type timeline struct {
+	capacity int
+	interval int
+	width    int
+	height   int
+	groups   []timelineGroup
+	length   int
+	head     engine.Board
+}
+
+func newTimeline(capacity, interval int) *timeline {
+	return &timeline{capacity: capacity, interval: interval}
+}
+
+func (t *timeline) reset(board engine.Board, generation int) {
+	t.groups = nil
+	t.length = 0
+	t.width = board.Width()
+	t.height = board.Height()
+	t.push(board, generation)
+}
+
+func (t *timeline) push(board engine.Board, generation int) {
+	if board.Width() != t.width || board.Height() != t.height {
+		t.reset(board, generation)
+		return
+	}
+	last := len(t.groups) - 1
+	if last < 0 || t.groups[last].frames() >= t.interval {
+		t.groups = append(t.groups, timelineGroup{
+			keyframe:    packBoard(board),
+			generations: []int{generation},
+		})
+	} else {
+		group := &t.groups[last]
+		group.deltas = append(group.deltas, xorDelta(t.head, board))
+		group.generations = append(group.generations, generation)
+	}
+	t.head = board
+	t.length++
+	for t.length > t.capacity && len(t.groups) > 1 {
+		t.length -= t.groups[0].frames()
+		t.groups = t.groups[1:]
+	}
+}
+
+func (t *timeline) truncate(length int) {
+	if length >= t.length || length <= 0 {
+		return
+	}
+	remaining := length
+	for i := range t.groups {
+		frames := t.groups[i].frames()
+		if remaining <= frames {
+			t.groups[i].generations = t.groups[i].generations[:remaining]
+			t.groups[i].deltas = t.groups[i].deltas[:remaining-1]
+			t.groups = t.groups[:i+1]
+			break
+		}
+		remaining -= frames
+	}
+	t.length = length
+	t.head, _ = t.frame(length - 1)
+}
+
+func (t *timeline) frame(index int) (engine.Board, int) {
+	for _, group := range t.groups {
+		if index >= group.frames() {
+			index -= group.frames()
+			continue
+		}
+		board := unpackBoard(group.keyframe, t.width, t.height)
+		for _, delta := range group.deltas[:index] {
+			applyDelta(&board, delta)
+		}
+		return board, group.generations[index]
+	}
+	return t.head, 0
+}
+
+func packBoard(board engine.Board) []uint64 {
+	width := board.Width()
+	bits := make([]uint64, (width*board.Height()+63)/64)
+	for y := 0; y < board.Height(); y++ {
+		for x := 0; x < width; x++ {
+			if board.IsAlive(x, y) {
+				index := y*width + x
+				bits[index/64] |= 1 << (index % 64)
+			}
+		}
+	}
+	return bits
+}
+
+func unpackBoard(bits []uint64, width, height int) engine.Board {
+	board := engine.NewBoard(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			index := y*width + x
+			if bits[index/64]&(1<<(index%64)) != 0 {
+				board.SetAlive(x, y, true)
+			}
+		}
+	}
+	return board
+}
+
+func xorDelta(from, to engine.Board) []int32 {
+	delta := make([]int32, 0)
+	for y := 0; y < to.Height(); y++ {
+		for x := 0; x < to.Width(); x++ {
+			if from.IsAlive(x, y) != to.IsAlive(x, y) {
+				delta = append(delta, int32(y*to.Width()+x))
+			}
+		}
+	}
+	return delta
+}
+
+func applyDelta(board *engine.Board, delta []int32) {
+	width := board.Width()
+	for _, index := range delta {
+		x := int(index) % width
+		y := int(index) / width
+		board.SetAlive(x, y, !board.IsAlive(x, y))
+	}
+}
+
+func (s *Simulation) resetTimeline() {
+	s.timeline.reset(s.board, s.generation)
+	s.timelineCursor = 0
+	s.ownsBoard, s.editsPending = false, false
+	s.resetCycles()
+}
+
+func (s *Simulation) resetCycles() {
+	s.cycles.reset()
+	s.cycles.observe(s.board, s.generation)
+	s.cycle, s.cycling = Cycle{}, false
+}
+
+func (s *Simulation) recordTimeline() {
+	s.timeline.truncate(s.timelineCursor + 1)
+	s.timeline.push(s.board, s.generation)
+	s.timelineCursor = s.timeline.length - 1
+	s.ownsBoard = false
+}
+
+func (s *Simulation) replaceTimelineHead() {
+	if s.timelineCursor > 0 {
+		s.timeline.truncate(s.timelineCursor)
+		s.timeline.push(s.board, s.generation)
+		s.timelineCursor = s.timeline.length - 1
+		s.ownsBoard, s.editsPending = false, false
+		s.resetCycles()
+		return
+	}
+	s.resetTimeline()
+}
+
+func (s *Simulation) Rewind(steps int) int {
+	return s.seekTimeline(-steps)
+}
+
+func (s *Simulation) FastForward(steps int) int {
+	return s.seekTimeline(steps)
+}
+
+func (s *Simulation) seekTimeline(offset int) int {
+	s.flushEdits()
+	target := s.timelineCursor + offset
+	if target < 0 {
+		target = 0
+	}
+	if target > s.timeline.length-1 {
+		target = s.timeline.length - 1
+	}
+	moved := target - s.timelineCursor
+	if moved == 0 {
+		return 0
+	}
+	s.board, s.generation = s.timeline.frame(target)
+	s.timelineCursor = target
+	s.ownsBoard = false
+	s.stableGenerations = 0
+	s.resetCycles()
+	if moved < 0 {
+		s.replaced++
+		return -moved
+	}
+	return moved
+}
+
+func (s *Simulation) TimelinePosition() (int, int) {
+	return s.timelineCursor, s.timeline.length
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldRewindToExactPastGenerations(t *testing.T) {
+	sim := NewSimulation(16, 16, 11)
+	boards := []engine.Board{sim.Board()}
+	for i := 0; i < 70; i++ {
+		sim.Tick()
+		boards = append(boards, sim.Board())
+	}
+
+	for _, steps := range []int{1, 5, 33, 31} {
+		sim.Rewind(steps)
+		position, _ := sim.TimelinePosition()
+		if sim.Generation() != position || !boardsEqual(boards[position], sim.Board()) {
+			t.Fatalf("expected board of generation %d after rewinding, got generation %d", position, sim.Generation())
+		}
+	}
+	if sim.Generation() != 0 {
+		t.Fatalf("expected to reach generation 0, got %d", sim.Generation())
+	}
+	if moved := sim.Rewind(1); moved != 0 {
+		t.Fatalf("expected no movement before start of timeline, moved %d", moved)
+	}
+}
+
+func TestShouldFastForwardWithinRecordedTimeline(t *testing.T) {
+	sim := NewSimulation(10, 10, 2)
+	for i := 0; i < 10; i++ {
+		sim.Tick()
+	}
+	latest := sim.Board()
+
+	sim.Rewind(6)
+	moved := sim.FastForward(100)
+
+	if moved != 6 || sim.Generation() != 10 || !boardsEqual(latest, sim.Board()) {
+		t.Fatalf("expected fast forward back to generation 10, moved %d to generation %d", moved, sim.Generation())
+	}
+}
+
+func TestShouldDiscardFutureWhenResumingFromRewoundPosition(t *testing.T) {
+	sim := NewSimulation(10, 10, 2)
+	for i := 0; i < 10; i++ {
+		sim.Tick()
+	}
+
+	sim.Rewind(4)
+	sim.Tick()
+
+	position, length := sim.TimelinePosition()
+	if sim.Generation() != 7 || position != 7 || length != 8 {
+		t.Fatalf("expected branch at generation 7 (pos %d of %d), got generation %d", position, length, sim.Generation())
+	}
+}
+
+func TestShouldEvictOldestKeyframeGroupsBeyondCapacity(t *testing.T) {
+	line := newTimeline(64, 16)
+	board := engine.NewBoard(4, 4)
+	line.reset(board, 0)
+	for gen := 1; gen < 100; gen++ {
+		next := board.Clone()
+		next.SetAlive(gen%4, (gen/4)%4, !next.IsAlive(gen%4, (gen/4)%4))
+		board = next
+		line.push(board, gen)
+	}
+
+	if line.length > 64 {
+		t.Fatalf("expected timeline bounded by capacity, got %d frames", line.length)
+	}
+	last, generation := line.frame(line.length - 1)
+	if generation != 99 || !boardsEqual(last, board) {
+		t.Fatalf("expected newest frame to reconstruct generation 99, got %d", generation)
+	}
+}
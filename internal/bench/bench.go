@@ -0,0 +1,182 @@
+// Package bench times the engines on random soups, for the bench command.
+// It reports the same gens/s and cells/s metrics as the Go benchmarks in
+// internal/engine.
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"gol-on-cli/internal/engine"
+)
+
+const (
+	EngineBoard    = "board"
+	EngineUniverse = "universe"
+	// restartEvery matches the Go benchmarks.
+	restartEvery = 256
+)
+
+// Engines lists the engine implementations a case can run.
+func Engines() []string {
+	return []string{EngineBoard, EngineUniverse}
+}
+
+// Case is one engine on one random soup. The universe engine starts from
+// the same cells and runs them on an unbounded plane.
+type Case struct {
+	Engine  string  `json:"engine"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Density float64 `json:"density"`
+}
+
+// Name is the benchmark name, in the key=value form benchstat groups by.
+func (c Case) Name() string {
+	return fmt.Sprintf("engine=%s/size=%dx%d/density=%.2f", c.Engine, c.Width, c.Height, c.Density)
+}
+
+// Result is one timed run. CellsPerSec counts the soup's area per
+// generation for both engines, so they compare on the same work.
+type Result struct {
+	Case
+	Generations int     `json:"generations"`
+	NsPerGen    float64 `json:"ns_per_gen"`
+	GensPerSec  float64 `json:"gens_per_sec"`
+	CellsPerSec float64 `json:"cells_per_sec"`
+}
+
+type Options struct {
+	Seed int64
+	// Duration is how long each case runs, unless Generations fixes the
+	// number of generations instead.
+	Duration    time.Duration
+	Generations int
+}
+
+// Cases is every combination of engines, sizes and densities, in that
+// order.
+func Cases(engines []string, sizes [][2]int, densities []float64) []Case {
+	var cases []Case
+	for _, name := range engines {
+		for _, size := range sizes {
+			for _, density := range densities {
+				cases = append(cases, Case{Engine: name, Width: size[0], Height: size[1], Density: density})
+			}
+		}
+	}
+	return cases
+}
+
+func Run(c Case, options Options) (Result, error) {
+	step, err := stepper(c, Soup(options.Seed, c.Width, c.Height, c.Density))
+	if err != nil {
+		return Result{}, err
+	}
+	generations := 0
+	started := time.Now()
+	for {
+		if options.Generations > 0 && generations >= options.Generations {
+			break
+		}
+		if options.Generations <= 0 && generations > 0 && time.Since(started) >= options.Duration {
+			break
+		}
+		step(generations%restartEvery == 0)
+		generations++
+	}
+	elapsed := max(time.Since(started).Seconds(), 1e-9)
+	result := Result{Case: c, Generations: generations}
+	result.NsPerGen = elapsed * 1e9 / float64(generations)
+	result.GensPerSec = float64(generations) / elapsed
+	result.CellsPerSec = result.GensPerSec * float64(c.Width*c.Height)
+	return result, nil
+}
+
+// stepper returns a function advancing the engine one generation, from the
+// starting soup again when restart is set.
+func stepper(c Case, soup engine.Board) (func(restart bool), error) {
+	rule := engine.ConwayRule()
+	switch c.Engine {
+	case EngineBoard:
+		board := soup
+		return func(restart bool) {
+			if restart {
+				board = soup
+			}
+			board = board.NextGenerationWithRule(rule)
+		}, nil
+	case EngineUniverse:
+		start := engine.UniverseFromBoard(soup, 0, 0)
+		universe := start
+		return func(restart bool) {
+			if restart {
+				universe = start
+			}
+			universe = universe.Step(rule)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown engine %q (available: %s)", c.Engine, strings.Join(Engines(), ", "))
+}
+
+// Soup fills a board at random; the same seed always gives the same soup.
+func Soup(seed int64, width, height int, density float64) engine.Board {
+	rng := rand.New(rand.NewSource(seed))
+	board := engine.NewBoard(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if rng.Float64() < density {
+				board.SetAlive(x, y, true)
+			}
+		}
+	}
+	return board
+}
+
+// ParseEngines reads a comma-separated engine list.
+func ParseEngines(list string) ([]string, error) {
+	var engines []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name != EngineBoard && name != EngineUniverse {
+			return nil, fmt.Errorf("unknown engine %q (available: %s)", name, strings.Join(Engines(), ", "))
+		}
+		engines = append(engines, name)
+	}
+	return engines, nil
+}
+
+// ParseSizes reads a comma-separated list of WxH or N sizes.
+func ParseSizes(list string) ([][2]int, error) {
+	var sizes [][2]int
+	for _, size := range strings.Split(list, ",") {
+		size = strings.TrimSpace(strings.ToLower(size))
+		w, h, found := strings.Cut(size, "x")
+		if !found {
+			h = w
+		}
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if errW != nil || errH != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("invalid size %q: use WxH or N", size)
+		}
+		sizes = append(sizes, [2]int{width, height})
+	}
+	return sizes, nil
+}
+
+// ParseDensities reads a comma-separated list of fractions between 0 and 1.
+func ParseDensities(list string) ([]float64, error) {
+	var densities []float64
+	for _, text := range strings.Split(list, ",") {
+		density, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || density < 0 || density > 1 {
+			return nil, fmt.Errorf("invalid density %q: must be between 0 and 1", strings.TrimSpace(text))
+		}
+		densities = append(densities, density)
+	}
+	return densities, nil
+}
@@ -0,0 +1,101 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestShouldRunFixedGenerationsForEveryEngine(t *testing.T) {
+	for _, name := range Engines() {
+		result, err := Run(Case{Engine: name, Width: 16, Height: 16, Density: 0.3}, Options{Seed: 1, Generations: 300})
+		if err != nil {
+			t.Fatalf("%s: failed to run: %v", name, err)
+		}
+		if result.Generations != 300 || result.GensPerSec <= 0 || result.CellsPerSec != result.GensPerSec*256 {
+			t.Fatalf("%s: unexpected result %+v", name, result)
+		}
+	}
+	if _, err := Run(Case{Engine: "hashlife", Width: 4, Height: 4}, Options{Generations: 1}); err == nil {
+		t.Fatalf("expected an unknown engine to fail")
+	}
+}
+
+func TestShouldBuildSoupsFromSeed(t *testing.T) {
+	first, again, other := Soup(7, 20, 20, 0.5), Soup(7, 20, 20, 0.5), Soup(8, 20, 20, 0.5)
+	same, differs := true, false
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			same = same && first.IsAlive(x, y) == again.IsAlive(x, y)
+			differs = differs || first.IsAlive(x, y) != other.IsAlive(x, y)
+		}
+	}
+	if !same || !differs {
+		t.Fatalf("expected soups to depend only on the seed")
+	}
+}
+
+func TestShouldParseCaseLists(t *testing.T) {
+	engines, err := ParseEngines("board, Universe")
+	if err != nil || len(engines) != 2 || engines[1] != EngineUniverse {
+		t.Fatalf("unexpected engines %v %v", engines, err)
+	}
+	sizes, err := ParseSizes("64,32x16")
+	if err != nil || sizes[0] != [2]int{64, 64} || sizes[1] != [2]int{32, 16} {
+		t.Fatalf("unexpected sizes %v %v", sizes, err)
+	}
+	densities, err := ParseDensities("0.1,0.5")
+	if err != nil || len(densities) != 2 {
+		t.Fatalf("unexpected densities %v %v", densities, err)
+	}
+	if cases := Cases(engines, sizes, densities); len(cases) != 8 || cases[0].Name() != "engine=board/size=64x64/density=0.10" {
+		t.Fatalf("unexpected cases %+v", cases)
+	}
+	for _, bad := range []func() error{
+		func() error { _, err := ParseEngines("gpu"); return err },
+		func() error { _, err := ParseSizes("0x4"); return err },
+		func() error { _, err := ParseDensities("1.5"); return err },
+		func() error { _, err := ParseFormat("xml"); return err },
+	} {
+		if bad() == nil {
+			t.Fatalf("expected invalid input to be rejected")
+		}
+	}
+}
+
+func TestShouldWriteBenchstatCompatibleLines(t *testing.T) {
+	report := NewReport(1, []Result{{Case: Case{Engine: EngineBoard, Width: 64, Height: 64, Density: 0.3}, Generations: 500, NsPerGen: 400000, GensPerSec: 2500, CellsPerSec: 10240000}})
+	var out bytes.Buffer
+
+	if err := report.Write(&out, FormatBenchstat); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[2] != "pkg: gol-on-cli/internal/bench" {
+		t.Fatalf("unexpected header %q", lines[:3])
+	}
+	line := regexp.MustCompile(`^BenchmarkEngine/engine=board/size=64x64/density=0\.30-\d+\t500\t400000 ns/op\t2500 gens/s\t1\.024e\+07 cells/s$`)
+	if !line.MatchString(lines[3]) {
+		t.Fatalf("unexpected benchmark line %q", lines[3])
+	}
+}
+
+func TestShouldWriteJSONReport(t *testing.T) {
+	report := NewReport(3, []Result{{Case: Case{Engine: EngineUniverse, Width: 8, Height: 8, Density: 0.5}, Generations: 10}})
+	var out bytes.Buffer
+
+	if err := report.Write(&out, FormatJSON); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected JSON, got %v: %s", err, out.String())
+	}
+	if decoded.Seed != 3 || decoded.GoVersion == "" || len(decoded.Results) != 1 || decoded.Results[0].Engine != EngineUniverse {
+		t.Fatalf("unexpected report %+v", decoded)
+	}
+}
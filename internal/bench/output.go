@@ -0,0 +1,82 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatBenchstat  = "benchstat"
+	benchstatPackage = "gol-on-cli/internal/bench"
+)
+
+// Report is the JSON document: the results plus what they were measured
+// on.
+type Report struct {
+	GoVersion  string   `json:"go_version"`
+	GOOS       string   `json:"goos"`
+	GOARCH     string   `json:"goarch"`
+	GOMAXPROCS int      `json:"gomaxprocs"`
+	Seed       int64    `json:"seed"`
+	Results    []Result `json:"results"`
+}
+
+func NewReport(seed int64, results []Result) Report {
+	return Report{
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Seed:       seed,
+		Results:    results,
+	}
+}
+
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case FormatText, FormatJSON, FormatBenchstat:
+		return strings.ToLower(name), nil
+	}
+	return "", fmt.Errorf("unknown format %q (available: text, json, benchstat)", name)
+}
+
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case FormatBenchstat:
+		return r.writeBenchstat(w)
+	}
+	return r.writeText(w)
+}
+
+// writeBenchstat prints the Go benchmark format: a configuration header
+// and one line per result with iterations, ns/op and the custom metrics.
+func (r Report) writeBenchstat(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "goos: %s\ngoarch: %s\npkg: %s\n", r.GOOS, r.GOARCH, benchstatPackage)
+	for _, result := range r.Results {
+		fmt.Fprintf(&b, "BenchmarkEngine/%s-%d\t%d\t%.0f ns/op\t%.4g gens/s\t%.4g cells/s\n",
+			result.Name(), r.GOMAXPROCS, result.Generations, result.NsPerGen, result.GensPerSec, result.CellsPerSec)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (r Report) writeText(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "engine\tsize\tdensity\tgenerations\tns/gen\tgens/s\tcells/s\t")
+	for _, result := range r.Results {
+		fmt.Fprintf(table, "%s\t%dx%d\t%.2f\t%d\t%.0f\t%.1f\t%.3g\t\n",
+			result.Engine, result.Width, result.Height, result.Density, result.Generations, result.NsPerGen, result.GensPerSec, result.CellsPerSec)
+	}
+	return table.Flush()
+}
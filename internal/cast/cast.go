@@ -0,0 +1,133 @@
+package cast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const formatVersion = 2
+
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type Event struct {
+	Time float64
+	Kind string
+	Data string
+}
+
+type Writer struct {
+	out   *bufio.Writer
+	start time.Time
+	now   func() time.Time
+}
+
+func NewWriter(w io.Writer, header Header, now func() time.Time) (*Writer, error) {
+	if now == nil {
+		now = time.Now
+	}
+	header.Version = formatVersion
+	start := now()
+	if header.Timestamp == 0 {
+		header.Timestamp = start.Unix()
+	}
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	out := bufio.NewWriter(w)
+	if _, err := out.Write(append(encoded, '\n')); err != nil {
+		return nil, err
+	}
+	return &Writer{out: out, start: start, now: now}, nil
+}
+
+func (w *Writer) WriteOutput(data string) error {
+	elapsed := w.now().Sub(w.start).Seconds()
+	encoded, err := json.Marshal([]any{elapsed, "o", data})
+	if err != nil {
+		return err
+	}
+	if _, err := w.out.Write(append(encoded, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *Writer) Flush() error {
+	return w.out.Flush()
+}
+
+func Read(r io.Reader) (Header, []Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Header{}, nil, err
+		}
+		return Header{}, nil, fmt.Errorf("invalid asciicast: missing header")
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return Header{}, nil, fmt.Errorf("invalid asciicast header: %v", err)
+	}
+	if header.Version != formatVersion {
+		return Header{}, nil, fmt.Errorf("unsupported asciicast version: %d", header.Version)
+	}
+
+	events := make([]Event, 0)
+	line := 1
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var raw []any
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil || len(raw) != 3 {
+			return Header{}, nil, fmt.Errorf("invalid asciicast event on line %d", line)
+		}
+		at, okTime := raw[0].(float64)
+		kind, okKind := raw[1].(string)
+		data, okData := raw[2].(string)
+		if !okTime || !okKind || !okData {
+			return Header{}, nil, fmt.Errorf("invalid asciicast event on line %d", line)
+		}
+		events = append(events, Event{Time: at, Kind: kind, Data: data})
+	}
+	if err := scanner.Err(); err != nil {
+		return Header{}, nil, err
+	}
+	return header, events, nil
+}
+
+func Play(w io.Writer, events []Event, speed float64, sleep func(time.Duration)) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid speed: must be greater than zero")
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	last := 0.0
+	for _, event := range events {
+		if event.Kind != "o" {
+			continue
+		}
+		if gap := event.Time - last; gap > 0 {
+			sleep(time.Duration(gap / speed * float64(time.Second)))
+		}
+		last = event.Time
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShouldWriteHeaderAndTimestampedOutputEvents(t *testing.T) {
+	clock := time.Unix(1700000000, 0)
+	now := func() time.Time { return clock }
+	var buf bytes.Buffer
+
+	writer, err := NewWriter(&buf, Header{Width: 80, Height: 24}, now)
+	if err != nil {
+		t.Fatalf("expected writer, got %v", err)
+	}
+	clock = clock.Add(1500 * time.Millisecond)
+	if err := writer.WriteOutput("\x1b[Hframe"); err != nil {
+		t.Fatalf("expected write to succeed, got %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("expected flush to succeed, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one event, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `"version":2`) || !strings.Contains(lines[0], `"width":80`) {
+		t.Fatalf("expected v2 header with width, got %q", lines[0])
+	}
+	if lines[1] != `[1.5,"o","\u001b[Hframe"]` {
+		t.Fatalf("unexpected event line %q", lines[1])
+	}
+}
+
+func TestShouldRoundTripRecordedEvents(t *testing.T) {
+	input := `{"version":2,"width":10,"height":5}
+[0.1,"o","a"]
+[0.5,"o","b"]
+`
+	header, events, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected read to succeed, got %v", err)
+	}
+	if header.Width != 10 || len(events) != 2 || events[1].Data != "b" {
+		t.Fatalf("unexpected parse result: %+v %+v", header, events)
+	}
+}
+
+func TestShouldRejectUnsupportedVersion(t *testing.T) {
+	if _, _, err := Read(strings.NewReader(`{"version":1}`)); err == nil {
+		t.Fatalf("expected error for asciicast v1")
+	}
+}
+
+func TestShouldPlayEventsWithScaledDelays(t *testing.T) {
+	events := []Event{{Time: 1, Kind: "o", Data: "a"}, {Time: 3, Kind: "o", Data: "b"}}
+	var slept []time.Duration
+	var out bytes.Buffer
+
+	if err := Play(&out, events, 2, func(d time.Duration) { slept = append(slept, d) }); err != nil {
+		t.Fatalf("expected play to succeed, got %v", err)
+	}
+
+	if out.String() != "ab" {
+		t.Fatalf("expected replayed output, got %q", out.String())
+	}
+	if len(slept) != 2 || slept[0] != 500*time.Millisecond || slept[1] != time.Second {
+		t.Fatalf("expected delays halved by speed, got %v", slept)
+	}
+}
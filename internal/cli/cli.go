@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"gol-on-cli/internal/input"
 	"gol-on-cli/internal/pattern"
 )
 
@@ -21,24 +22,39 @@ type StartResult struct {
 }
 
 func Start(options StartOptions, loader Loader) (StartResult, error) {
-	if options.FPS <= 0 {
-		return StartResult{}, fmt.Errorf("invalid fps: must be greater than zero")
+	if err := ValidateStartOptions(options); err != nil {
+		return StartResult{}, err
 	}
 	if options.PatternURL == "" {
 		return StartResult{}, nil
 	}
-	if !pattern.ValidateWikiURL(options.PatternURL) {
-		return StartResult{}, fmt.Errorf("invalid pattern-url: must match https://conwaylife.com/wiki/...")
-	}
 	if err := loader.Load(options.PatternURL); err != nil {
 		return StartResult{PatternLoadAttempted: true}, err
 	}
 	return StartResult{PatternLoadAttempted: true}, nil
 }
 
+// ValidateStartOptions checks startup options without loading anything.
+func ValidateStartOptions(options StartOptions) error {
+	if options.FPS <= 0 {
+		return fmt.Errorf("invalid fps: must be greater than zero")
+	}
+	if options.PatternURL != "" && !pattern.ValidateWikiURL(options.PatternURL) {
+		return fmt.Errorf("invalid pattern-url: must match https://conwaylife.com/wiki/...")
+	}
+	return nil
+}
+
 func BuildHelpText() string {
-	return strings.Join([]string{
-		"Usage: gol-on-cli [options]",
+	return BuildHelpTextWithKeymap(input.DefaultKeymap())
+}
+
+func BuildHelpTextWithKeymap(keymap *input.Keymap) string {
+	lines := []string{
+		"Usage: gol-on-cli [run] [options]",
+		"       gol-on-cli <command> [options]",
+		"",
+		"Run \"gol-on-cli help\" for the list of commands (export, snapshot, replay, config, ...).",
 		"",
 		"Options:",
 		"  --help          Show usage and options",
@@ -46,13 +62,58 @@ func BuildHelpText() string {
 		"  --fps <n>       Set updates per second",
 		"  --seed <n>      Set random seed",
 		"  --pattern-url   Load ConwayLife Wiki pattern on startup",
+		"  --trail <n>     Fade dead cells out over n generations",
+		"  --record <file> Record the session as asciicast v2",
+		"  --keymap <file> Key binding file (action = key, key; preset = vim|emacs)",
+		"  --keys-preset   Key binding preset: default, vim or emacs",
+		"  --config <file> Config file (default $XDG_CONFIG_HOME/gol-on-cli/config.toml)",
+		"  --color <mode>  Color mode: truecolor or 256",
+		"",
+		"Soups:",
+		"  --soup-density <f>        Fraction of live cells, 0-1 (default 0.5)",
+		"  --soup-size <WxH|full>    Seeded window size (default 20x20)",
+		"  --soup-placement <mode>   Window placement: center or random",
+		"  --soup-symmetry <name>    C1, C2_1, C2_2, C2_4, C4_1, C4_4, D2_+1, D2_+2, D2_x,",
+		"                            D4_+1, D4_+2, D4_+4, D4_x1, D4_x4, D8_1 or D8_4",
+		"  --seed-string <s>         String seed hashed with SHA-256, e.g. k_abc123",
+		"  --soup <seed#n>           Start at an exact soup ID (press c to copy the current one)",
+		"",
+		"Auto-restart:",
+		"  --restart <list>          Restart on still, cycle, population, generations, or never",
+		"  --restart-linger <n>      Generations a still life or cycle lingers first (default 100)",
+		"  --restart-population <n>  Restart when the population drops below n",
+		"  --restart-generations <n> Restart after n generations",
+		"  --restart-fade <dur>      Fade between soups, e.g. 400ms (0 disables)",
+		"  --census-neighborhood <n> Group census objects by moore or vonneumann, e.g. moore:2",
+		"",
+		"Headless:",
+		"  --headless          Stream frames to stdout instead of the TUI",
+		"  --generations <n>   Number of generations to stream",
+		"  --every <n>         Emit a frame every n generations",
+		"  --ansi              Emit colored ANSI frames instead of plain text",
+		"  --cursor-home       Prefix each frame with a cursor-home escape",
+		"  --width, --height   Board size in headless mode",
+		"  --stats             Append population, births, deaths, density and box per frame",
 		"",
-		"Shortcuts:",
-		"  q, h/?, space, r, l",
+	}
+	lines = append(lines, "Shortcuts:")
+	for _, line := range keymap.HelpLines() {
+		lines = append(lines, "  "+line)
+	}
+	lines = append(lines,
+		"  (mouse in edit mode: left paints, right erases, shift+drag selects)",
+		"",
+		"Commands (press : then Tab to complete, Up/Down for history):",
+	)
+	for _, usage := range input.CommandUsages() {
+		lines = append(lines, "  "+usage)
+	}
+	lines = append(lines,
 		"",
 		"URL Example:",
 		"  https://conwaylife.com/wiki/Glider",
-	}, "\n")
+	)
+	return strings.Join(lines, "\n")
 }
 
 func BuildVersionText(version string) string {
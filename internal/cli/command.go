@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Exit codes shared by every subcommand.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+)
+
+// Command is one subcommand. Args is the synopsis after the command name,
+// e.g. "[options] <in> [out]".
+type Command struct {
+	Name    string
+	Args    string
+	Summary string
+	Run     func(ctx *Context) int
+}
+
+// Context carries a command's arguments and streams, and a flag set whose
+// usage output is the same for every command.
+type Context struct {
+	Program string
+	Command Command
+	Flags   *flag.FlagSet
+	Args    []string
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// Parse parses Args with Flags. When ok is false the command should return
+// code: ExitOK after -h, ExitUsage after a bad flag.
+func (c *Context) Parse() (code int, ok bool) {
+	err := c.Flags.Parse(c.Args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK, false
+	case err != nil:
+		return ExitUsage, false
+	}
+	return ExitOK, true
+}
+
+// UsageError reports a wrong invocation together with the synopsis.
+func (c *Context) UsageError(format string, args ...any) int {
+	fmt.Fprintf(c.Stderr, "%s %s: %s\n", c.Program, c.Command.Name, fmt.Sprintf(format, args...))
+	fmt.Fprintf(c.Stderr, "usage: %s\n", c.synopsis())
+	return ExitUsage
+}
+
+func (c *Context) PrintUsage() {
+	fmt.Fprintf(c.Flags.Output(), "Usage: %s\n\n%s\n", c.synopsis(), c.Command.Summary)
+	hasFlags := false
+	c.Flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(c.Flags.Output(), "\nOptions:")
+		c.Flags.PrintDefaults()
+	}
+}
+
+func (c *Context) synopsis() string {
+	return strings.TrimSpace(c.Program + " " + c.Command.Name + " " + c.Command.Args)
+}
+
+// Dispatcher picks a command from the first argument. With no arguments, or
+// when the first one is a flag, it runs Default so "gol-on-cli --fps 10"
+// keeps working.
+type Dispatcher struct {
+	Program  string
+	Default  string
+	Commands []Command
+}
+
+func (d Dispatcher) Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	name := d.Default
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		return d.help(args, stdout, stderr)
+	}
+	command, ok := d.find(name)
+	if !ok {
+		fmt.Fprintf(stderr, "%s: unknown command %q\n\n", d.Program, name)
+		d.PrintOverview(stderr)
+		return ExitUsage
+	}
+	return command.Run(d.context(command, args, stdin, stdout, stderr))
+}
+
+func (d Dispatcher) find(name string) (Command, bool) {
+	for _, command := range d.Commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+func (d Dispatcher) context(command Command, args []string, stdin io.Reader, stdout, stderr io.Writer) *Context {
+	ctx := &Context{
+		Program: d.Program,
+		Command: command,
+		Flags:   flag.NewFlagSet(d.Program+" "+command.Name, flag.ContinueOnError),
+		Args:    args,
+		Stdin:   stdin,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	}
+	ctx.Flags.SetOutput(stderr)
+	ctx.Flags.Usage = ctx.PrintUsage
+	return ctx
+}
+
+// help prints the command list, or one command's usage and options. The
+// command registers its flags when run with -h, so they are listed too.
+func (d Dispatcher) help(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		d.PrintOverview(stdout)
+		return ExitOK
+	}
+	command, ok := d.find(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "%s help: unknown command %q\n", d.Program, args[0])
+		return ExitUsage
+	}
+	ctx := d.context(command, []string{"-h"}, nil, stdout, stdout)
+	command.Run(ctx)
+	return ExitOK
+}
+
+func (d Dispatcher) PrintOverview(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [options]\n\nCommands:\n", d.Program)
+	width := len("help")
+	for _, command := range d.Commands {
+		width = max(width, len(command.Name))
+	}
+	for _, command := range d.Commands {
+		summary := command.Summary
+		if command.Name == d.Default {
+			summary += " (default)"
+		}
+		fmt.Fprintf(w, "  %-*s  %s\n", width, command.Name, summary)
+	}
+	fmt.Fprintf(w, "  %-*s  %s\n", width, "help", "Show this list, or a command's options")
+	fmt.Fprintf(w, "\nRun \"%s help <command>\" for a command's options.\n", d.Program)
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testDispatcher(ran *[]string) Dispatcher {
+	record := func(ctx *Context) int {
+		verbose := ctx.Flags.Bool("verbose", false, "talk more")
+		if code, ok := ctx.Parse(); !ok {
+			return code
+		}
+		if ctx.Command.Name == "convert" && ctx.Flags.NArg() != 2 {
+			return ctx.UsageError("expected input and output")
+		}
+		entry := ctx.Command.Name + ":" + strings.Join(ctx.Flags.Args(), ",")
+		if *verbose {
+			entry += ":verbose"
+		}
+		*ran = append(*ran, entry)
+		return ExitOK
+	}
+	return Dispatcher{
+		Program: "gol",
+		Default: "run",
+		Commands: []Command{
+			{Name: "run", Args: "[options]", Summary: "Run it", Run: record},
+			{Name: "convert", Args: "[options] <in> <out>", Summary: "Convert it", Run: record},
+		},
+	}
+}
+
+func TestShouldRunDefaultCommandForNoArgumentsOrLeadingFlag(t *testing.T) {
+	var ran []string
+	d := testDispatcher(&ran)
+	var out bytes.Buffer
+
+	d.Run(nil, nil, &out, &out)
+	d.Run([]string{"--verbose"}, nil, &out, &out)
+	d.Run([]string{"convert", "--verbose", "a.cells", "b.rle"}, nil, &out, &out)
+
+	want := []string{"run:", "run::verbose", "convert:a.cells,b.rle:verbose"}
+	if strings.Join(ran, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, ran)
+	}
+}
+
+func TestShouldUseUsageExitCodeForBadInvocations(t *testing.T) {
+	var ran []string
+	d := testDispatcher(&ran)
+	cases := map[string][]string{
+		"unknown command": {"frobnicate"},
+		"bad flag":        {"run", "--loud"},
+		"wrong arguments": {"convert", "only-one"},
+	}
+	for name, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := d.Run(args, nil, &stdout, &stderr); code != ExitUsage {
+			t.Fatalf("%s: expected exit %d, got %d", name, ExitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), "gol") {
+			t.Fatalf("%s: expected usage on stderr, got %q", name, stderr.String())
+		}
+	}
+	if len(ran) != 0 {
+		t.Fatalf("expected no command to finish, got %v", ran)
+	}
+}
+
+func TestShouldPrintCommandListAndPerCommandHelp(t *testing.T) {
+	var ran []string
+	d := testDispatcher(&ran)
+	var stdout, stderr bytes.Buffer
+
+	if code := d.Run([]string{"help"}, nil, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected help to succeed, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "run      Run it (default)") || !strings.Contains(stdout.String(), "convert  Convert it") {
+		t.Fatalf("expected command list, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := d.Run([]string{"help", "convert"}, nil, &stdout, &stderr); code != ExitOK {
+		t.Fatalf("expected command help to succeed, got %d", code)
+	}
+	for _, want := range []string{"Usage: gol convert [options] <in> <out>", "Convert it", "-verbose"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in command help, got %q", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := d.Run([]string{"convert", "-h"}, nil, &stdout, &stderr); code != ExitOK || !strings.Contains(stderr.String(), "Usage: gol convert") {
+		t.Fatalf("expected -h to print usage and succeed, got %d %q", code, stderr.String())
+	}
+}
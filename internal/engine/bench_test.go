@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// benchmarkRestartEvery keeps long benchmark runs on the early, busy part of
+// a soup instead of whatever it settles into.
+const benchmarkRestartEvery = 256
+
+var (
+	benchmarkSizes     = []int{64, 256, 1024}
+	benchmarkDensities = []float64{0.1, 0.3, 0.5}
+)
+
+func benchmarkSoup(width, height int, density float64) Board {
+	rng := rand.New(rand.NewSource(1))
+	board := NewBoard(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			board.cells[y][x] = rng.Float64() < density
+		}
+	}
+	return board
+}
+
+func reportThroughput(b *testing.B, width, height int) {
+	gensPerSecond := float64(b.N) / b.Elapsed().Seconds()
+	b.ReportMetric(gensPerSecond, "gens/s")
+	b.ReportMetric(gensPerSecond*float64(width*height), "cells/s")
+}
+
+func BenchmarkBoardNextGeneration(b *testing.B) {
+	rule := ConwayRule()
+	for _, size := range benchmarkSizes {
+		for _, density := range benchmarkDensities {
+			b.Run(fmt.Sprintf("size=%dx%d/density=%.2f", size, size, density), func(b *testing.B) {
+				start := benchmarkSoup(size, size, density)
+				board := start
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if i%benchmarkRestartEvery == 0 {
+						board = start
+					}
+					board = board.NextGenerationWithRule(rule)
+				}
+				reportThroughput(b, size, size)
+			})
+		}
+	}
+}
+
+func BenchmarkUniverseStep(b *testing.B) {
+	rule := ConwayRule()
+	for _, size := range benchmarkSizes[:2] {
+		for _, density := range benchmarkDensities {
+			b.Run(fmt.Sprintf("size=%dx%d/density=%.2f", size, size, density), func(b *testing.B) {
+				start := UniverseFromBoard(benchmarkSoup(size, size, density), 0, 0)
+				universe := start
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if i%benchmarkRestartEvery == 0 {
+						universe = start
+					}
+					universe = universe.Step(rule)
+				}
+				reportThroughput(b, size, size)
+			})
+		}
+	}
+}
@@ -30,6 +30,14 @@ func (b *Board) SetAlive(x, y int, alive bool) {
 	b.cells[y][x] = alive
 }
 
+func (b Board) Clone() Board {
+	clone := NewBoard(b.width, b.height)
+	for y := 0; y < b.height; y++ {
+		copy(clone.cells[y], b.cells[y])
+	}
+	return clone
+}
+
 func (b Board) IsAlive(x, y int) bool {
 	if !b.inBounds(x, y) {
 		return false
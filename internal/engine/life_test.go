@@ -124,3 +124,18 @@ func TestShouldSafelyHandleNextGenerationOnZeroSizedBoard(t *testing.T) {
 		t.Fatalf("expected zero-sized board to stay zero-sized, got %dx%d", next.Width(), next.Height())
 	}
 }
+
+func TestShouldCloneBoardIndependently(t *testing.T) {
+	board := NewBoard(2, 2)
+	board.SetAlive(0, 0, true)
+
+	clone := board.Clone()
+	clone.SetAlive(1, 1, true)
+
+	if board.IsAlive(1, 1) {
+		t.Fatalf("expected original board to stay unchanged after editing clone")
+	}
+	if !clone.IsAlive(0, 0) {
+		t.Fatalf("expected clone to keep original cells")
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Rule struct {
+	Birth    [9]bool
+	Survival [9]bool
+}
+
+func ConwayRule() Rule {
+	var rule Rule
+	rule.Birth[3] = true
+	rule.Survival[2] = true
+	rule.Survival[3] = true
+	return rule
+}
+
+// ParseRule reads B/S notation such as B3/S23, in either order, or the
+// older numeric S/B notation such as 23/3 that RLE headers still use.
+func ParseRule(text string) (Rule, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(text))
+	birthPart, survivalPart, ok := strings.Cut(normalized, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("invalid rule %q: expected B<digits>/S<digits>", text)
+	}
+	if !strings.ContainsAny(normalized, "BS") {
+		birthPart, survivalPart = "B"+survivalPart, "S"+birthPart
+	}
+	if strings.HasPrefix(birthPart, "S") {
+		birthPart, survivalPart = survivalPart, birthPart
+	}
+	if !strings.HasPrefix(birthPart, "B") || !strings.HasPrefix(survivalPart, "S") {
+		return Rule{}, fmt.Errorf("invalid rule %q: expected B<digits>/S<digits>", text)
+	}
+
+	var rule Rule
+	if err := parseRuleDigits(birthPart[1:], &rule.Birth); err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %v", text, err)
+	}
+	if err := parseRuleDigits(survivalPart[1:], &rule.Survival); err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %v", text, err)
+	}
+	return rule, nil
+}
+
+func parseRuleDigits(digits string, target *[9]bool) error {
+	for _, char := range digits {
+		if char < '0' || char > '8' {
+			return fmt.Errorf("neighbor count %q out of range 0-8", char)
+		}
+		target[char-'0'] = true
+	}
+	return nil
+}
+
+func (r Rule) String() string {
+	var b strings.Builder
+	b.WriteRune('B')
+	for count, enabled := range r.Birth {
+		if enabled {
+			fmt.Fprintf(&b, "%d", count)
+		}
+	}
+	b.WriteString("/S")
+	for count, enabled := range r.Survival {
+		if enabled {
+			fmt.Fprintf(&b, "%d", count)
+		}
+	}
+	return b.String()
+}
+
+func (b Board) NextGenerationWithRule(rule Rule) Board {
+	next := NewBoard(b.width, b.height)
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			neighbors := b.aliveNeighbors(x, y)
+			if b.cells[y][x] {
+				next.cells[y][x] = rule.Survival[neighbors]
+			} else {
+				next.cells[y][x] = rule.Birth[neighbors]
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,73 @@
+package engine
+
+import "testing"
+
+func TestShouldParseAndFormatRuleStrings(t *testing.T) {
+	rule, err := ParseRule("b36/s23")
+	if err != nil {
+		t.Fatalf("expected rule to parse, got %v", err)
+	}
+
+	if rule.String() != "B36/S23" {
+		t.Fatalf("expected canonical rule string, got %q", rule.String())
+	}
+	if ConwayRule().String() != "B3/S23" {
+		t.Fatalf("expected Conway rule B3/S23, got %q", ConwayRule().String())
+	}
+}
+
+func TestShouldParseNumericSurvivalBirthRules(t *testing.T) {
+	for text, want := range map[string]string{"23/3": "B3/S23", "23/36": "B36/S23", "/2": "B2/S"} {
+		rule, err := ParseRule(text)
+		if err != nil {
+			t.Fatalf("expected rule %q to parse, got %v", text, err)
+		}
+		if rule.String() != want {
+			t.Fatalf("expected %q to read as %q, got %q", text, want, rule.String())
+		}
+	}
+	if _, err := ParseRule("23/9"); err == nil {
+		t.Fatalf("expected an out of range numeric rule to fail")
+	}
+}
+
+func TestShouldRejectMalformedRules(t *testing.T) {
+	for _, text := range []string{"B3S23", "B9/S23", "X3/S23", ""} {
+		if _, err := ParseRule(text); err == nil {
+			t.Fatalf("expected error for rule %q", text)
+		}
+	}
+}
+
+func TestShouldMatchConwayNextGenerationWithConwayRule(t *testing.T) {
+	board := NewBoard(5, 5)
+	board.SetAlive(1, 2, true)
+	board.SetAlive(2, 2, true)
+	board.SetAlive(3, 2, true)
+
+	expected := board.NextGeneration()
+	actual := board.NextGenerationWithRule(ConwayRule())
+
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			if expected.IsAlive(x, y) != actual.IsAlive(x, y) {
+				t.Fatalf("expected Conway rule to match default evolution at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestShouldBirthWithSixNeighborsUnderHighLife(t *testing.T) {
+	rule, _ := ParseRule("B36/S23")
+	board := NewBoard(5, 5)
+	for _, p := range [][2]int{{1, 1}, {2, 1}, {3, 1}, {1, 3}, {2, 3}, {3, 3}} {
+		board.SetAlive(p[0], p[1], true)
+	}
+
+	if !board.NextGenerationWithRule(rule).IsAlive(2, 2) {
+		t.Fatalf("expected HighLife birth with six neighbors")
+	}
+	if board.NextGeneration().IsAlive(2, 2) {
+		t.Fatalf("expected Conway rule to keep cell dead with six neighbors")
+	}
+}
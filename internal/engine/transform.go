@@ -0,0 +1,80 @@
+package engine
+
+func (b Board) Crop(x, y, width, height int) Board {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	cropped := NewBoard(width, height)
+	for dy := 0; dy < height; dy++ {
+		for dx := 0; dx < width; dx++ {
+			if b.IsAlive(x+dx, y+dy) {
+				cropped.cells[dy][dx] = true
+			}
+		}
+	}
+	return cropped
+}
+
+func (b Board) RotateClockwise() Board {
+	rotated := NewBoard(b.height, b.width)
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			if b.cells[y][x] {
+				rotated.cells[x][b.height-1-y] = true
+			}
+		}
+	}
+	return rotated
+}
+
+func (b Board) FlipHorizontal() Board {
+	flipped := NewBoard(b.width, b.height)
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			flipped.cells[y][b.width-1-x] = b.cells[y][x]
+		}
+	}
+	return flipped
+}
+
+func (b Board) FlipVertical() Board {
+	flipped := NewBoard(b.width, b.height)
+	for y := 0; y < b.height; y++ {
+		copy(flipped.cells[b.height-1-y], b.cells[y])
+	}
+	return flipped
+}
+
+func (b Board) Population() int {
+	count := 0
+	for y := 0; y < b.height; y++ {
+		for x := 0; x < b.width; x++ {
+			if b.cells[y][x] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// BoundingBox returns the smallest rectangle containing every live cell, or
+// all zeros for an empty board.
+func (b Board) BoundingBox() (x, y, width, height int) {
+	minX, minY, maxX, maxY := b.width, b.height, -1, -1
+	for cy := 0; cy < b.height; cy++ {
+		for cx := 0; cx < b.width; cx++ {
+			if !b.cells[cy][cx] {
+				continue
+			}
+			minX, maxX = min(minX, cx), max(maxX, cx)
+			minY, maxY = min(minY, cy), max(maxY, cy)
+		}
+	}
+	if maxX < 0 {
+		return 0, 0, 0, 0
+	}
+	return minX, minY, maxX - minX + 1, maxY - minY + 1
+}
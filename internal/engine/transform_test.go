@@ -0,0 +1,60 @@
+package engine
+
+import "testing"
+
+func TestShouldCropRegionAndIgnoreOutOfBoundsCells(t *testing.T) {
+	board := NewBoard(4, 4)
+	board.SetAlive(1, 1, true)
+	board.SetAlive(3, 3, true)
+
+	cropped := board.Crop(1, 1, 4, 2)
+
+	if cropped.Width() != 4 || cropped.Height() != 2 {
+		t.Fatalf("expected 4x2 crop, got %dx%d", cropped.Width(), cropped.Height())
+	}
+	if !cropped.IsAlive(0, 0) || cropped.Population() != 1 {
+		t.Fatalf("expected only the in-range cell in crop")
+	}
+}
+
+func TestShouldRotateClockwiseSwappingDimensions(t *testing.T) {
+	board := NewBoard(3, 1)
+	board.SetAlive(0, 0, true)
+
+	rotated := board.RotateClockwise()
+
+	if rotated.Width() != 1 || rotated.Height() != 3 {
+		t.Fatalf("expected 1x3 after rotation, got %dx%d", rotated.Width(), rotated.Height())
+	}
+	if !rotated.IsAlive(0, 0) {
+		t.Fatalf("expected left cell to move to top after clockwise rotation")
+	}
+	if !board.RotateClockwise().RotateClockwise().RotateClockwise().RotateClockwise().IsAlive(0, 0) {
+		t.Fatalf("expected four rotations to restore original")
+	}
+}
+
+func TestShouldFlipHorizontallyAndVertically(t *testing.T) {
+	board := NewBoard(2, 2)
+	board.SetAlive(0, 0, true)
+
+	if !board.FlipHorizontal().IsAlive(1, 0) {
+		t.Fatalf("expected horizontal flip to mirror x")
+	}
+	if !board.FlipVertical().IsAlive(0, 1) {
+		t.Fatalf("expected vertical flip to mirror y")
+	}
+}
+
+func TestShouldReportBoundingBoxOfLiveCells(t *testing.T) {
+	board := NewBoard(10, 8)
+	board.SetAlive(2, 3, true)
+	board.SetAlive(6, 5, true)
+
+	if x, y, w, h := board.BoundingBox(); x != 2 || y != 3 || w != 5 || h != 3 {
+		t.Fatalf("expected 5x3 box at 2,3, got %dx%d at %d,%d", w, h, x, y)
+	}
+	if _, _, w, h := NewBoard(4, 4).BoundingBox(); w != 0 || h != 0 {
+		t.Fatalf("expected empty box for empty board, got %dx%d", w, h)
+	}
+}
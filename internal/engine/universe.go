@@ -0,0 +1,135 @@
+package engine
+
+import "sort"
+
+type Cell struct {
+	X int
+	Y int
+}
+
+// Universe is an unbounded plane of live cells, for runs where patterns must
+// not wrap around like they do on a Board.
+type Universe struct {
+	// cells are keyed by packCell, which keeps map hashing cheap
+	cells map[uint64]struct{}
+}
+
+func NewUniverse(cells ...Cell) Universe {
+	u := Universe{cells: make(map[uint64]struct{}, len(cells))}
+	for _, c := range cells {
+		u.cells[packCell(c.X, c.Y)] = struct{}{}
+	}
+	return u
+}
+
+func packCell(x, y int) uint64 {
+	return uint64(uint32(int32(x)))<<32 | uint64(uint32(int32(y)))
+}
+
+func unpackCell(key uint64) Cell {
+	return Cell{X: int(int32(uint32(key >> 32))), Y: int(int32(uint32(key)))}
+}
+
+// UniverseFromBoard places the board's top-left corner at (x, y).
+func UniverseFromBoard(board Board, x, y int) Universe {
+	u := NewUniverse()
+	for row := 0; row < board.height; row++ {
+		for col := 0; col < board.width; col++ {
+			if board.cells[row][col] {
+				u.cells[packCell(x+col, y+row)] = struct{}{}
+			}
+		}
+	}
+	return u
+}
+
+func (u Universe) IsAlive(x, y int) bool {
+	_, ok := u.cells[packCell(x, y)]
+	return ok
+}
+
+func (u Universe) Population() int {
+	return len(u.cells)
+}
+
+// Bounds is the smallest rectangle holding every live cell; ok is false for
+// an empty universe.
+func (u Universe) Bounds() (x, y, width, height int, ok bool) {
+	if len(u.cells) == 0 {
+		return 0, 0, 0, 0, false
+	}
+	first := true
+	var minX, minY, maxX, maxY int
+	for key := range u.cells {
+		c := unpackCell(key)
+		if first {
+			minX, minY, maxX, maxY = c.X, c.Y, c.X, c.Y
+			first = false
+			continue
+		}
+		minX, minY = min(minX, c.X), min(minY, c.Y)
+		maxX, maxY = max(maxX, c.X), max(maxY, c.Y)
+	}
+	return minX, minY, maxX - minX + 1, maxY - minY + 1, true
+}
+
+// Each calls fn for every live cell, in no particular order.
+func (u Universe) Each(fn func(c Cell)) {
+	for key := range u.cells {
+		fn(unpackCell(key))
+	}
+}
+
+// Cells lists the live cells in row-major order.
+func (u Universe) Cells() []Cell {
+	cells := make([]Cell, 0, len(u.cells))
+	for key := range u.cells {
+		cells = append(cells, unpackCell(key))
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+	return cells
+}
+
+// Step advances one generation. Rules with B0 would fill the infinite
+// plane, so births on zero neighbors are ignored.
+func (u Universe) Step(rule Rule) Universe {
+	neighbors := make(map[uint64]uint8, len(u.cells)*4)
+	for key := range u.cells {
+		c := unpackCell(key)
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx != 0 || dy != 0 {
+					neighbors[packCell(c.X+dx, c.Y+dy)]++
+				}
+			}
+		}
+	}
+	next := Universe{cells: make(map[uint64]struct{}, len(u.cells))}
+	for key, count := range neighbors {
+		_, alive := u.cells[key]
+		if (alive && rule.Survival[count]) || (!alive && rule.Birth[count]) {
+			next.cells[key] = struct{}{}
+		}
+	}
+	for key := range u.cells {
+		if _, counted := neighbors[key]; !counted && rule.Survival[0] {
+			next.cells[key] = struct{}{}
+		}
+	}
+	return next
+}
+
+// Board copies the cells inside the given rectangle onto a new board.
+func (u Universe) Board(x, y, width, height int) Board {
+	board := NewBoard(width, height)
+	for key := range u.cells {
+		c := unpackCell(key)
+		board.SetAlive(c.X-x, c.Y-y, true)
+	}
+	return board
+}
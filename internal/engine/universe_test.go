@@ -0,0 +1,35 @@
+package engine
+
+import "testing"
+
+func TestShouldMoveGliderAcrossUnboundedUniverse(t *testing.T) {
+	u := NewUniverse(Cell{1, 0}, Cell{2, 1}, Cell{0, 2}, Cell{1, 2}, Cell{2, 2})
+
+	for i := 0; i < 400; i++ {
+		u = u.Step(ConwayRule())
+	}
+
+	x, y, w, h, ok := u.Bounds()
+	if !ok || x != 100 || y != 100 || w != 3 || h != 3 || u.Population() != 5 {
+		t.Fatalf("expected the glider 100 cells down-right without wrapping, got %dx%d@%d,%d", w, h, x, y)
+	}
+}
+
+func TestShouldConvertBetweenBoardAndUniverse(t *testing.T) {
+	board := NewBoard(3, 1)
+	board.SetAlive(0, 0, true)
+	board.SetAlive(2, 0, true)
+
+	u := UniverseFromBoard(board, -5, 7)
+	back := u.Board(-5, 7, 3, 1)
+
+	if !u.IsAlive(-5, 7) || !u.IsAlive(-3, 7) || u.Population() != 2 {
+		t.Fatalf("expected cells offset to -5,7, got %v", u.Cells())
+	}
+	if !back.IsAlive(0, 0) || !back.IsAlive(2, 0) || back.Population() != 2 {
+		t.Fatalf("expected the board back unchanged")
+	}
+	if _, _, _, _, ok := NewUniverse().Bounds(); ok {
+		t.Fatalf("expected an empty universe to have no bounds")
+	}
+}
@@ -0,0 +1,245 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const maxCommandHistory = 100
+
+// Commands lists what the command line accepts, with an example argument
+// for the help screens.
+var Commands = []struct{ Name, Example string }{
+	{"census", "out.csv|out.json"},
+	{"fps", "30"},
+	{"goto", "1000"},
+	{"load", "<url|file>"},
+	{"rule", "B36/S23"},
+	{"save", "out.rle"},
+	{"seed", "42"},
+}
+
+var CommandNames = commandNames()
+
+func commandNames() []string {
+	names := make([]string, len(Commands))
+	for i, command := range Commands {
+		names[i] = command.Name
+	}
+	return names
+}
+
+// CommandUsages returns ":name example" for each command.
+func CommandUsages() []string {
+	usages := make([]string, len(Commands))
+	for i, command := range Commands {
+		usages[i] = ":" + command.Name + " " + command.Example
+	}
+	return usages
+}
+
+var CommonRules = []string{"B3/S23", "B36/S23", "B3678/S34678", "B368/S245", "B2/S", "B1357/S1357", "B3/S012345678"}
+
+type CommandLine struct {
+	Active      bool
+	Candidates  []string
+	buffer      []rune
+	cursor      int
+	history     []string
+	recall      int
+	draft       string
+	globPattern func(pattern string) ([]string, error)
+}
+
+func NewCommandLine() *CommandLine {
+	return &CommandLine{globPattern: filepath.Glob}
+}
+
+func (c *CommandLine) Open() {
+	c.Active = true
+	c.buffer = c.buffer[:0]
+	c.cursor = 0
+	c.recall = len(c.history)
+	c.Candidates = nil
+}
+
+func (c *CommandLine) Close() {
+	c.Active = false
+	c.Candidates = nil
+}
+
+func (c *CommandLine) Text() string {
+	return string(c.buffer)
+}
+
+func (c *CommandLine) Cursor() int {
+	return c.cursor
+}
+
+func (c *CommandLine) History() []string {
+	return c.history
+}
+
+// HandleKey edits the line for a named key ("enter", "left", ...) or inserts
+// r when key is empty. It returns the submitted command once enter is pressed.
+func (c *CommandLine) HandleKey(key string, r rune) (string, bool) {
+	if key != "tab" {
+		c.Candidates = nil
+	}
+	switch key {
+	case "":
+		c.insert(string(r))
+	case "enter":
+		command := strings.TrimSpace(c.Text())
+		c.Close()
+		if command != "" {
+			c.remember(command)
+		}
+		return command, command != ""
+	case "esc", "ctrl-c", "ctrl-g":
+		c.Close()
+	case "backspace":
+		if c.cursor > 0 {
+			c.buffer = append(c.buffer[:c.cursor-1], c.buffer[c.cursor:]...)
+			c.cursor--
+		} else if len(c.buffer) == 0 {
+			c.Close()
+		}
+	case "delete":
+		if c.cursor < len(c.buffer) {
+			c.buffer = append(c.buffer[:c.cursor], c.buffer[c.cursor+1:]...)
+		}
+	case "left":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "right":
+		if c.cursor < len(c.buffer) {
+			c.cursor++
+		}
+	case "home", "ctrl-a":
+		c.cursor = 0
+	case "end", "ctrl-e":
+		c.cursor = len(c.buffer)
+	case "ctrl-u":
+		c.buffer = append(c.buffer[:0], c.buffer[c.cursor:]...)
+		c.cursor = 0
+	case "up":
+		c.recallHistory(-1)
+	case "down":
+		c.recallHistory(1)
+	case "tab":
+		c.Complete()
+	}
+	return "", false
+}
+
+func (c *CommandLine) insert(text string) {
+	runes := []rune(text)
+	tail := append([]rune(nil), c.buffer[c.cursor:]...)
+	c.buffer = append(append(c.buffer[:c.cursor], runes...), tail...)
+	c.cursor += len(runes)
+}
+
+func (c *CommandLine) remember(command string) {
+	if n := len(c.history); n == 0 || c.history[n-1] != command {
+		c.history = append(c.history, command)
+	}
+	if len(c.history) > maxCommandHistory {
+		c.history = c.history[len(c.history)-maxCommandHistory:]
+	}
+	c.recall = len(c.history)
+}
+
+func (c *CommandLine) recallHistory(offset int) {
+	target := c.recall + offset
+	if target < 0 || target > len(c.history) {
+		return
+	}
+	if c.recall == len(c.history) {
+		c.draft = c.Text()
+	}
+	c.recall = target
+	text := c.draft
+	if target < len(c.history) {
+		text = c.history[target]
+	}
+	c.buffer = []rune(text)
+	c.cursor = len(c.buffer)
+}
+
+// Complete extends the word before the cursor: command names first, then file
+// paths for load/save and well-known rules for rule. Ambiguous matches extend
+// to their common prefix and are left in Candidates for display.
+func (c *CommandLine) Complete() {
+	before := string(c.buffer[:c.cursor])
+	name, argument, hasArgument := strings.Cut(before, " ")
+	var word string
+	var matches []string
+	switch {
+	case !hasArgument:
+		word = name
+		matches = withPrefix(CommandNames, name)
+	case name == "load" || name == "save":
+		word = strings.TrimLeft(argument, " ")
+		matches = c.completePath(word)
+	case name == "rule":
+		word = strings.TrimLeft(argument, " ")
+		matches = withPrefix(CommonRules, strings.ToUpper(word))
+	}
+	if len(matches) == 0 {
+		c.Candidates = nil
+		return
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 && !hasArgument {
+		completion += " "
+	}
+	c.Candidates = nil
+	if len(matches) > 1 {
+		c.Candidates = matches
+	}
+	if len(completion) < len(word) {
+		return
+	}
+	c.buffer = append(c.buffer[:c.cursor-len([]rune(word))], c.buffer[c.cursor:]...)
+	c.cursor -= len([]rune(word))
+	c.insert(completion)
+}
+
+func (c *CommandLine) completePath(prefix string) []string {
+	matches, err := c.globPattern(prefix + "*")
+	if err != nil {
+		return nil
+	}
+	for i, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			matches[i] = match + string(filepath.Separator)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func withPrefix(words []string, prefix string) []string {
+	matches := make([]string, 0)
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			matches = append(matches, word)
+		}
+	}
+	return matches
+}
+
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, string(prefix)) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return string(prefix)
+}
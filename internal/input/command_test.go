@@ -0,0 +1,97 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func typeCommand(c *CommandLine, text string) {
+	for _, r := range text {
+		c.HandleKey("", r)
+	}
+}
+
+func TestShouldSubmitTypedCommandAndRecallHistory(t *testing.T) {
+	c := NewCommandLine()
+	c.Open()
+	typeCommand(c, "fps 30")
+
+	line, ok := c.HandleKey("enter", 0)
+	if !ok || line != "fps 30" || c.Active {
+		t.Fatalf("expected submitted command and closed line, got %q %v %v", line, ok, c.Active)
+	}
+
+	c.Open()
+	typeCommand(c, "go")
+	c.HandleKey("up", 0)
+	if c.Text() != "fps 30" {
+		t.Fatalf("expected history recall, got %q", c.Text())
+	}
+	c.HandleKey("down", 0)
+	if c.Text() != "go" {
+		t.Fatalf("expected draft restored after history, got %q", c.Text())
+	}
+}
+
+func TestShouldEditAtCursorAndCancelOnEscape(t *testing.T) {
+	c := NewCommandLine()
+	c.Open()
+	typeCommand(c, "sed 1")
+	for i := 0; i < 3; i++ {
+		c.HandleKey("left", 0)
+	}
+	c.HandleKey("", 'e')
+
+	if c.Text() != "seed 1" || c.Cursor() != 3 {
+		t.Fatalf("expected insertion at cursor, got %q at %d", c.Text(), c.Cursor())
+	}
+	if _, ok := c.HandleKey("esc", 0); ok || c.Active {
+		t.Fatalf("expected escape to cancel without submitting")
+	}
+}
+
+func TestShouldCompleteCommandNamesAndRules(t *testing.T) {
+	c := NewCommandLine()
+	c.Open()
+	typeCommand(c, "ru")
+	c.HandleKey("tab", 0)
+	if c.Text() != "rule " {
+		t.Fatalf("expected command completion, got %q", c.Text())
+	}
+
+	typeCommand(c, "b36/")
+	c.HandleKey("tab", 0)
+	if c.Text() != "rule B36/S23" {
+		t.Fatalf("expected rule completion, got %q", c.Text())
+	}
+
+	c.Open()
+	typeCommand(c, "s")
+	c.HandleKey("tab", 0)
+	if c.Text() != "s" || len(c.Candidates) != 2 {
+		t.Fatalf("expected ambiguous completion to list candidates, got %q %v", c.Text(), c.Candidates)
+	}
+}
+
+func TestShouldCutCommonPrefixBetweenRunes(t *testing.T) {
+	if prefix := commonPrefix([]string{"패턴.rle", "패토.rle"}); prefix != "패" {
+		t.Fatalf("expected the shared rune only, got %q", prefix)
+	}
+}
+
+func TestShouldCompleteFilePathsForLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "glider.rle"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	c := NewCommandLine()
+	c.Open()
+	typeCommand(c, "load "+filepath.Join(dir, "gl"))
+
+	c.HandleKey("tab", 0)
+
+	if c.Text() != "load "+filepath.Join(dir, "glider.rle") {
+		t.Fatalf("expected path completion, got %q", c.Text())
+	}
+}
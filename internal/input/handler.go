@@ -1,31 +1,261 @@
 package input
 
+type PenMode int
+
+const (
+	PenNone PenMode = iota
+	PenDraw
+	PenErase
+)
+
 type State struct {
 	Paused               bool
 	HelpVisible          bool
+	HelpScroll           int
+	StatsVisible         bool
 	LoadPatternRequested bool
+	SnapshotRequested    bool
+	StepRequested        bool
+	ToggleRequested      bool
+	CommandRequested     bool
+	EditMode             bool
+	Pen                  PenMode
+	CursorX              int
+	CursorY              int
+	Selecting            bool
+	AnchorX              int
+	AnchorY              int
+	Pasting              bool
+	SelectionAction      Action
 	ShouldQuit           bool
+	keymap               *Keymap
 }
 
 func NewState() *State {
-	return &State{}
+	return NewStateWithKeymap(DefaultKeymap())
+}
+
+func NewStateWithKeymap(keymap *Keymap) *State {
+	return &State{keymap: keymap}
+}
+
+func (s *State) Keymap() *Keymap {
+	return s.keymap
+}
+
+func (s *State) ResolveKey(key string) (Action, bool) {
+	if s.EditMode {
+		if action, ok := s.keymap.Lookup(ContextEdit, key); ok {
+			return action, true
+		}
+	}
+	return s.keymap.Lookup(ContextGlobal, key)
 }
 
-func (s *State) HandleKey(key string) {
-	switch key {
-	case "space":
+const helpPageSize = 10
+
+func (s *State) HandleKey(key string) Action {
+	if s.HelpVisible && s.handleHelpKey(key) {
+		return ""
+	}
+	action, ok := s.ResolveKey(key)
+	if !ok {
+		return ""
+	}
+	if s.HelpVisible && action != ActionHelp && action != ActionQuit {
+		return ""
+	}
+	s.HandleAction(action)
+	return action
+}
+
+func (s *State) HandleAction(action Action) {
+	if s.EditMode && s.handleEditAction(action) {
+		return
+	}
+	switch action {
+	case ActionTogglePause:
 		s.Paused = !s.Paused
-	case "h", "?":
+	case ActionHelp:
 		s.HelpVisible = !s.HelpVisible
-	case "l":
+		s.HelpScroll = 0
+	case ActionLoadPattern:
 		s.LoadPatternRequested = true
-	case "q":
+	case ActionSnapshot:
+		s.SnapshotRequested = true
+	case ActionStatsPanel:
+		s.StatsVisible = !s.StatsVisible
+	case ActionCommand:
+		s.CommandRequested = true
+	case ActionStep:
+		if s.Paused {
+			s.StepRequested = true
+		}
+	case ActionEditMode:
+		s.EditMode = !s.EditMode
+		s.Pen = PenNone
+		s.Selecting = false
+		s.Pasting = false
+	case ActionQuit:
 		s.ShouldQuit = true
 	}
 }
 
+// handleHelpKey keeps the help overlay modal: only scrolling, closing and
+// quitting get through while it is open.
+func (s *State) handleHelpKey(key string) bool {
+	action, ok := s.keymap.Lookup(ContextHelp, key)
+	if !ok {
+		return false
+	}
+	switch action {
+	case ActionHelpClose:
+		s.HelpVisible = false
+	case ActionHelpUp:
+		s.HelpScroll = max(0, s.HelpScroll-1)
+	case ActionHelpDown:
+		s.HelpScroll++
+	case ActionHelpPageUp:
+		s.HelpScroll = max(0, s.HelpScroll-helpPageSize)
+	case ActionHelpPageDown:
+		s.HelpScroll += helpPageSize
+	}
+	return true
+}
+
+func (s *State) handleEditAction(action Action) bool {
+	switch action {
+	case ActionCursorUp:
+		s.CursorY--
+	case ActionCursorDown:
+		s.CursorY++
+	case ActionCursorLeft:
+		s.CursorX--
+	case ActionCursorRight:
+		s.CursorX++
+	case ActionCommit:
+		if s.Pasting {
+			s.SelectionAction = ActionPaste
+			s.Pasting = false
+		} else {
+			s.ToggleRequested = true
+		}
+	case ActionToggleCell:
+		s.ToggleRequested = true
+	case ActionSelect:
+		if s.Selecting {
+			s.Selecting = false
+		} else {
+			s.BeginSelection(s.CursorX, s.CursorY)
+		}
+	case ActionPaste:
+		if s.Pasting {
+			s.SelectionAction = ActionPaste
+		}
+		s.Pasting = !s.Pasting
+		s.Selecting = false
+	case ActionCopy, ActionCut, ActionClear, ActionFill, ActionRandomize:
+		if s.Selecting {
+			s.SelectionAction = action
+		}
+	case ActionRotate, ActionFlipHorizontal, ActionFlipVertical:
+		if s.Selecting || s.Pasting {
+			s.SelectionAction = action
+		}
+	case ActionPenDraw:
+		s.Pen = togglePen(s.Pen, PenDraw)
+	case ActionPenErase:
+		s.Pen = togglePen(s.Pen, PenErase)
+	case ActionCancel:
+		if s.Pasting {
+			s.Pasting = false
+		} else if s.Selecting {
+			s.Selecting = false
+		} else if s.Pen != PenNone {
+			s.Pen = PenNone
+		} else {
+			s.EditMode = false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *State) BeginSelection(x, y int) {
+	s.Selecting = true
+	s.Pasting = false
+	s.AnchorX = x
+	s.AnchorY = y
+	s.CursorX = x
+	s.CursorY = y
+}
+
+func (s *State) Selection() (x1, y1, x2, y2 int) {
+	return s.AnchorX, s.AnchorY, s.CursorX, s.CursorY
+}
+
+func (s *State) ConsumeSelectionAction() Action {
+	action := s.SelectionAction
+	s.SelectionAction = ""
+	return action
+}
+
+func togglePen(current, requested PenMode) PenMode {
+	if current == requested {
+		return PenNone
+	}
+	return requested
+}
+
+func (s *State) ClampCursor(width, height int) {
+	s.CursorX = clamp(s.CursorX, 0, width-1)
+	s.CursorY = clamp(s.CursorY, 0, height-1)
+	s.AnchorX = clamp(s.AnchorX, 0, width-1)
+	s.AnchorY = clamp(s.AnchorY, 0, height-1)
+}
+
+func (s *State) MoveCursorTo(x, y int) {
+	s.CursorX = x
+	s.CursorY = y
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
+
 func (s *State) ConsumeLoadPatternRequest() bool {
 	requested := s.LoadPatternRequested
 	s.LoadPatternRequested = false
 	return requested
 }
+
+func (s *State) ConsumeSnapshotRequest() bool {
+	requested := s.SnapshotRequested
+	s.SnapshotRequested = false
+	return requested
+}
+
+func (s *State) ConsumeStepRequest() bool {
+	requested := s.StepRequested
+	s.StepRequested = false
+	return requested
+}
+
+func (s *State) ConsumeToggleRequest() bool {
+	requested := s.ToggleRequested
+	s.ToggleRequested = false
+	return requested
+}
+
+func (s *State) ConsumeCommandRequest() bool {
+	requested := s.CommandRequested
+	s.CommandRequested = false
+	return requested
+}
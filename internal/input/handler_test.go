@@ -1,6 +1,9 @@
 package input
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestShouldTogglePlayPauseWhenSpaceIsPressed(t *testing.T) {
 	state := NewState()
@@ -61,3 +64,177 @@ func TestShouldResetLoadPatternFlagAfterConsume(t *testing.T) {
 		t.Fatalf("expected second consume to be false after reset")
 	}
 }
+
+func TestShouldRequestSnapshotOnceWhenSIsPressed(t *testing.T) {
+	state := NewState()
+	state.HandleKey("s")
+
+	if !state.ConsumeSnapshotRequest() {
+		t.Fatalf("expected pending snapshot request after s")
+	}
+	if state.ConsumeSnapshotRequest() {
+		t.Fatalf("expected snapshot request to reset after consume")
+	}
+}
+
+func TestShouldRequestSingleStepOnlyWhilePaused(t *testing.T) {
+	state := NewState()
+
+	state.HandleKey("n")
+	if state.ConsumeStepRequest() {
+		t.Fatalf("expected n to be ignored while running")
+	}
+
+	state.HandleKey("space")
+	state.HandleKey("n")
+	if !state.ConsumeStepRequest() {
+		t.Fatalf("expected n to request a step while paused")
+	}
+}
+
+func TestShouldMoveAndClampCursorInEditMode(t *testing.T) {
+	state := NewState()
+	state.HandleKey("e")
+
+	state.HandleKey("right")
+	state.HandleKey("right")
+	state.HandleKey("down")
+	state.HandleKey("up")
+	state.HandleKey("up")
+	state.ClampCursor(2, 2)
+
+	if state.CursorX != 1 || state.CursorY != 0 {
+		t.Fatalf("expected cursor at (1,0), got (%d,%d)", state.CursorX, state.CursorY)
+	}
+}
+
+func TestShouldSwitchPenModesAndLeaveEditModeWithEscape(t *testing.T) {
+	state := NewState()
+	state.HandleKey("e")
+
+	state.HandleKey("d")
+	if state.Pen != PenDraw {
+		t.Fatalf("expected draw pen after d")
+	}
+	state.HandleKey("x")
+	if state.Pen != PenErase {
+		t.Fatalf("expected erase pen after x")
+	}
+	state.HandleKey("esc")
+	if state.Pen != PenNone || !state.EditMode {
+		t.Fatalf("expected first escape to lift pen and stay in edit mode")
+	}
+	state.HandleKey("esc")
+	if state.EditMode {
+		t.Fatalf("expected second escape to leave edit mode")
+	}
+}
+
+func TestShouldRequestToggleOnlyInEditMode(t *testing.T) {
+	state := NewState()
+
+	state.HandleKey("t")
+	if state.ConsumeToggleRequest() {
+		t.Fatalf("expected toggle to be ignored outside edit mode")
+	}
+
+	state.HandleKey("e")
+	state.HandleKey("enter")
+	if !state.ConsumeToggleRequest() {
+		t.Fatalf("expected enter to toggle cell in edit mode")
+	}
+}
+
+func TestShouldSpanSelectionFromAnchorToCursor(t *testing.T) {
+	state := NewState()
+	state.HandleKey("e")
+	state.HandleKey("right")
+	state.HandleKey("v")
+	state.HandleKey("right")
+	state.HandleKey("down")
+
+	x1, y1, x2, y2 := state.Selection()
+
+	if !state.Selecting || x1 != 1 || y1 != 0 || x2 != 2 || y2 != 1 {
+		t.Fatalf("unexpected selection (%d,%d)-(%d,%d) selecting=%v", x1, y1, x2, y2, state.Selecting)
+	}
+}
+
+func TestShouldRequestSelectionActionsOnlyWithActiveSelection(t *testing.T) {
+	state := NewState()
+	state.HandleKey("e")
+
+	state.HandleKey("y")
+	if action := state.ConsumeSelectionAction(); action != "" {
+		t.Fatalf("expected no action without selection, got %q", action)
+	}
+
+	state.HandleKey("v")
+	for key, expected := range map[string]Action{"y": ActionCopy, "m": ActionCut, "i": ActionFill, "z": ActionRandomize, "delete": ActionClear, "o": ActionRotate} {
+		state.HandleKey(key)
+		if action := state.ConsumeSelectionAction(); action != expected {
+			t.Fatalf("expected %q for key %q, got %q", expected, key, action)
+		}
+	}
+}
+
+func TestShouldCommitPasteWithEnterAndCancelWithEscape(t *testing.T) {
+	state := NewState()
+	state.HandleKey("e")
+
+	state.HandleKey("p")
+	if !state.Pasting {
+		t.Fatalf("expected paste preview after p")
+	}
+	state.HandleKey("f")
+	if action := state.ConsumeSelectionAction(); action != ActionFlipHorizontal {
+		t.Fatalf("expected flip while pasting, got %q", action)
+	}
+	state.HandleKey("enter")
+	if action := state.ConsumeSelectionAction(); action != ActionPaste || state.Pasting {
+		t.Fatalf("expected enter to commit paste, got %q pasting=%v", action, state.Pasting)
+	}
+
+	state.HandleKey("p")
+	state.HandleKey("esc")
+	if state.Pasting || !state.EditMode {
+		t.Fatalf("expected escape to cancel paste but stay in edit mode")
+	}
+}
+
+func TestShouldKeepHelpOverlayModal(t *testing.T) {
+	state := NewState()
+	state.HandleKey("h")
+
+	if action := state.HandleKey("r"); action != "" {
+		t.Fatalf("expected restart to be swallowed while help is open, got %q", action)
+	}
+	state.HandleKey("down")
+	state.HandleKey("pgdn")
+	if state.HelpScroll != 11 {
+		t.Fatalf("expected help to scroll to 11, got %d", state.HelpScroll)
+	}
+	state.HandleKey("esc")
+	if state.HelpVisible {
+		t.Fatalf("expected esc to close help")
+	}
+}
+
+func TestShouldScrollHelpWithReboundKeys(t *testing.T) {
+	keymap, err := ParseKeymap(strings.NewReader("help-down = ctrl-e\nhelp-close = x\n"))
+	if err != nil {
+		t.Fatalf("expected keymap to parse, got %v", err)
+	}
+	state := NewStateWithKeymap(keymap)
+	state.HandleKey("h")
+
+	state.HandleKey("ctrl-e")
+	state.HandleKey("j")
+	if state.HelpScroll != 1 {
+		t.Fatalf("expected only ctrl-e to scroll help, got %d", state.HelpScroll)
+	}
+	state.HandleKey("x")
+	if state.HelpVisible {
+		t.Fatalf("expected rebound x to close help")
+	}
+}
@@ -0,0 +1,317 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type Action string
+
+type Context string
+
+const (
+	ContextGlobal Context = "global"
+	ContextEdit   Context = "edit"
+	ContextHelp   Context = "help"
+)
+
+const (
+	ActionQuit           Action = "quit"
+	ActionTogglePause    Action = "toggle-pause"
+	ActionHelp           Action = "help"
+	ActionRestart        Action = "restart"
+	ActionLoadPattern    Action = "load-pattern"
+	ActionSnapshot       Action = "snapshot"
+	ActionStep           Action = "step"
+	ActionStepBack       Action = "step-back"
+	ActionScrubBack      Action = "scrub-back"
+	ActionScrubForward   Action = "scrub-forward"
+	ActionUndo           Action = "undo"
+	ActionRedo           Action = "redo"
+	ActionCommand        Action = "command"
+	ActionFaster         Action = "faster"
+	ActionSlower         Action = "slower"
+	ActionStepLarger     Action = "step-larger"
+	ActionStepSmaller    Action = "step-smaller"
+	ActionMaxSpeed       Action = "max-speed"
+	ActionStatsPanel     Action = "stats-panel"
+	ActionCopySoupID     Action = "copy-soup-id"
+	ActionEditMode       Action = "edit-mode"
+	ActionCursorUp       Action = "cursor-up"
+	ActionCursorDown     Action = "cursor-down"
+	ActionCursorLeft     Action = "cursor-left"
+	ActionCursorRight    Action = "cursor-right"
+	ActionToggleCell     Action = "toggle-cell"
+	ActionCommit         Action = "commit"
+	ActionPenDraw        Action = "pen-draw"
+	ActionPenErase       Action = "pen-erase"
+	ActionSelect         Action = "select"
+	ActionCopy           Action = "copy"
+	ActionCut            Action = "cut"
+	ActionPaste          Action = "paste"
+	ActionRotate         Action = "rotate"
+	ActionFlipHorizontal Action = "flip-horizontal"
+	ActionFlipVertical   Action = "flip-vertical"
+	ActionClear          Action = "clear"
+	ActionFill           Action = "fill"
+	ActionRandomize      Action = "randomize"
+	ActionCancel         Action = "cancel"
+	ActionHelpClose      Action = "help-close"
+	ActionHelpUp         Action = "help-up"
+	ActionHelpDown       Action = "help-down"
+	ActionHelpPageUp     Action = "help-page-up"
+	ActionHelpPageDown   Action = "help-page-down"
+)
+
+type ActionSpec struct {
+	Name        Action
+	Description string
+	DefaultKeys []string
+	Context     Context
+	Hint        bool
+}
+
+var Registry = []ActionSpec{
+	{Name: ActionQuit, Description: "Quit", DefaultKeys: []string{"q"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionHelp, Description: "Toggle help", DefaultKeys: []string{"h", "?"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionTogglePause, Description: "Play / pause", DefaultKeys: []string{"space"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionRestart, Description: "Restart with a new random soup", DefaultKeys: []string{"r"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionLoadPattern, Description: "Reload the startup pattern URL", DefaultKeys: []string{"l"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionSnapshot, Description: "Save a PNG snapshot of the board", DefaultKeys: []string{"s"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionStep, Description: "Advance one generation while paused", DefaultKeys: []string{"n"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionStepBack, Description: "Step back one generation (pauses)", DefaultKeys: []string{"b"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionScrubBack, Description: "Scrub the timeline back 10 generations", DefaultKeys: []string{"["}, Context: ContextGlobal, Hint: true},
+	{Name: ActionScrubForward, Description: "Scrub the timeline forward 10 generations", DefaultKeys: []string{"]"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionEditMode, Description: "Toggle edit mode", DefaultKeys: []string{"e"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionUndo, Description: "Undo edits, loads, restarts and resizes", DefaultKeys: []string{"u"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionRedo, Description: "Redo", DefaultKeys: []string{"ctrl-r"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionFaster, Description: "Raise the frame rate", DefaultKeys: []string{"+", "="}, Context: ContextGlobal, Hint: true},
+	{Name: ActionSlower, Description: "Lower the frame rate", DefaultKeys: []string{"-"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionStepLarger, Description: "Double the generations computed per frame", DefaultKeys: []string{">"}, Context: ContextGlobal},
+	{Name: ActionStepSmaller, Description: "Halve the generations computed per frame", DefaultKeys: []string{"<"}, Context: ContextGlobal},
+	{Name: ActionMaxSpeed, Description: "Toggle max-speed mode (simulate between redraws)", DefaultKeys: []string{"w"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionStatsPanel, Description: "Toggle the statistics side panel", DefaultKeys: []string{"a"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionCopySoupID, Description: "Copy the current soup ID to the system clipboard", DefaultKeys: []string{"c"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionCommand, Description: "Open the command line (:load, :rule, :fps, :seed, :save, :goto)", DefaultKeys: []string{":"}, Context: ContextGlobal, Hint: true},
+	{Name: ActionCursorUp, Description: "Move cursor up", DefaultKeys: []string{"up"}, Context: ContextEdit},
+	{Name: ActionCursorDown, Description: "Move cursor down", DefaultKeys: []string{"down"}, Context: ContextEdit},
+	{Name: ActionCursorLeft, Description: "Move cursor left", DefaultKeys: []string{"left"}, Context: ContextEdit},
+	{Name: ActionCursorRight, Description: "Move cursor right", DefaultKeys: []string{"right"}, Context: ContextEdit},
+	{Name: ActionToggleCell, Description: "Toggle the cell under the cursor", DefaultKeys: []string{"t"}, Context: ContextEdit},
+	{Name: ActionCommit, Description: "Commit paste, or toggle the cell under the cursor", DefaultKeys: []string{"enter"}, Context: ContextEdit},
+	{Name: ActionPenDraw, Description: "Pen down: draw while moving", DefaultKeys: []string{"d"}, Context: ContextEdit},
+	{Name: ActionPenErase, Description: "Pen down: erase while moving", DefaultKeys: []string{"x"}, Context: ContextEdit},
+	{Name: ActionSelect, Description: "Start / stop rectangular selection", DefaultKeys: []string{"v"}, Context: ContextEdit},
+	{Name: ActionCopy, Description: "Copy selection (also to system clipboard as RLE)", DefaultKeys: []string{"y"}, Context: ContextEdit},
+	{Name: ActionCut, Description: "Cut selection", DefaultKeys: []string{"m"}, Context: ContextEdit},
+	{Name: ActionPaste, Description: "Paste preview / commit paste", DefaultKeys: []string{"p"}, Context: ContextEdit},
+	{Name: ActionRotate, Description: "Rotate selection or paste 90 degrees", DefaultKeys: []string{"o"}, Context: ContextEdit},
+	{Name: ActionFlipHorizontal, Description: "Flip selection or paste horizontally", DefaultKeys: []string{"f"}, Context: ContextEdit},
+	{Name: ActionFlipVertical, Description: "Flip selection or paste vertically", DefaultKeys: []string{"g"}, Context: ContextEdit},
+	{Name: ActionClear, Description: "Clear selection", DefaultKeys: []string{"delete", "backspace"}, Context: ContextEdit},
+	{Name: ActionFill, Description: "Fill selection", DefaultKeys: []string{"i"}, Context: ContextEdit},
+	{Name: ActionRandomize, Description: "Randomize selection", DefaultKeys: []string{"z"}, Context: ContextEdit},
+	{Name: ActionCancel, Description: "Cancel paste / selection / pen, then leave edit mode", DefaultKeys: []string{"esc"}, Context: ContextEdit},
+	{Name: ActionHelpClose, Description: "Close help", DefaultKeys: []string{"esc"}, Context: ContextHelp},
+	{Name: ActionHelpUp, Description: "Scroll help up a line", DefaultKeys: []string{"up", "k"}, Context: ContextHelp},
+	{Name: ActionHelpDown, Description: "Scroll help down a line", DefaultKeys: []string{"down", "j"}, Context: ContextHelp},
+	{Name: ActionHelpPageUp, Description: "Scroll help up a page", DefaultKeys: []string{"pgup"}, Context: ContextHelp},
+	{Name: ActionHelpPageDown, Description: "Scroll help down a page", DefaultKeys: []string{"pgdn", "space"}, Context: ContextHelp},
+}
+
+var presets = map[string]map[Action][]string{
+	"default": {},
+	"vim": {
+		ActionHelp:        {"?"},
+		ActionCursorLeft:  {"h", "left"},
+		ActionCursorDown:  {"j", "down"},
+		ActionCursorUp:    {"k", "up"},
+		ActionCursorRight: {"l", "right"},
+	},
+	"emacs": {
+		ActionCursorUp:    {"ctrl-p", "up"},
+		ActionCursorDown:  {"ctrl-n", "down"},
+		ActionCursorLeft:  {"ctrl-b", "left"},
+		ActionCursorRight: {"ctrl-f", "right"},
+		ActionSelect:      {"ctrl-space", "v"},
+		ActionCut:         {"ctrl-w", "m"},
+		ActionPaste:       {"ctrl-y", "p"},
+		ActionUndo:        {"ctrl-_", "u"},
+		ActionCancel:      {"ctrl-g", "esc"},
+	},
+}
+
+type Keymap struct {
+	bindings map[Action][]string
+}
+
+func FindAction(name Action) (ActionSpec, bool) {
+	for _, spec := range Registry {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return ActionSpec{}, false
+}
+
+func DefaultKeymap() *Keymap {
+	keymap := &Keymap{bindings: make(map[Action][]string, len(Registry))}
+	for _, spec := range Registry {
+		keymap.bindings[spec.Name] = append([]string(nil), spec.DefaultKeys...)
+	}
+	return keymap
+}
+
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (k *Keymap) ApplyPreset(name string) error {
+	preset, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("unknown key preset %q (available: %s)", name, strings.Join(PresetNames(), ", "))
+	}
+	for action, keys := range preset {
+		k.Bind(action, keys)
+	}
+	return nil
+}
+
+func (k *Keymap) Bind(action Action, keys []string) {
+	spec, _ := FindAction(action)
+	for other, bound := range k.bindings {
+		if other == action {
+			continue
+		}
+		otherSpec, _ := FindAction(other)
+		if otherSpec.Context != spec.Context {
+			continue
+		}
+		k.bindings[other] = withoutKeys(bound, keys)
+	}
+	k.bindings[action] = append([]string(nil), keys...)
+}
+
+func withoutKeys(bound, removed []string) []string {
+	kept := make([]string, 0, len(bound))
+	for _, key := range bound {
+		found := false
+		for _, r := range removed {
+			if key == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			kept = append(kept, key)
+		}
+	}
+	return kept
+}
+
+func (k *Keymap) Keys(action Action) []string {
+	return k.bindings[action]
+}
+
+func (k *Keymap) Lookup(context Context, key string) (Action, bool) {
+	for _, spec := range Registry {
+		if spec.Context != context {
+			continue
+		}
+		for _, bound := range k.bindings[spec.Name] {
+			if bound == key {
+				return spec.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (k *Keymap) Hint() string {
+	parts := make([]string, 0)
+	for _, spec := range Registry {
+		if !spec.Hint || len(k.bindings[spec.Name]) == 0 {
+			continue
+		}
+		parts = append(parts, strings.Join(k.bindings[spec.Name], "/"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (k *Keymap) HelpLines() []string {
+	lines := make([]string, 0, len(Registry))
+	for _, spec := range Registry {
+		keys := strings.Join(k.bindings[spec.Name], "/")
+		if keys == "" {
+			keys = "(unbound)"
+		}
+		if spec.Context != ContextGlobal {
+			keys = string(spec.Context) + ": " + keys
+		}
+		lines = append(lines, fmt.Sprintf("%-22s %-16s %s", keys, spec.Name, spec.Description))
+	}
+	return lines
+}
+
+func ParseKeymap(r io.Reader) (*Keymap, error) {
+	keymap := DefaultKeymap()
+	if err := keymap.Load(r); err != nil {
+		return nil, err
+	}
+	return keymap, nil
+}
+
+// normalizeKeyName lower-cases named keys such as Ctrl-Y; single characters
+// keep their case.
+func normalizeKeyName(key string) string {
+	if utf8.RuneCountInString(key) == 1 {
+		return key
+	}
+	return strings.ToLower(key)
+}
+
+// Load layers the bindings of a keys.conf file over the keymap.
+func (k *Keymap) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return fmt.Errorf("keymap line %d: expected \"action = key, key\"", line)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if name == "preset" {
+			if err := k.ApplyPreset(value); err != nil {
+				return fmt.Errorf("keymap line %d: %v", line, err)
+			}
+			continue
+		}
+		if _, ok := FindAction(Action(name)); !ok {
+			return fmt.Errorf("keymap line %d: unknown action %q", line, name)
+		}
+		keys := make([]string, 0)
+		for _, key := range strings.Split(value, ",") {
+			key = normalizeKeyName(strings.TrimSpace(key))
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+		k.Bind(Action(name), keys)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,94 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShouldResolveDefaultKeysThroughRegistry(t *testing.T) {
+	keymap := DefaultKeymap()
+
+	for key, expected := range map[string]Action{"q": ActionQuit, "?": ActionHelp, "space": ActionTogglePause, "ctrl-r": ActionRedo} {
+		if action, ok := keymap.Lookup(ContextGlobal, key); !ok || action != expected {
+			t.Fatalf("expected %q to resolve to %q, got %q", key, expected, action)
+		}
+	}
+}
+
+func TestShouldBuildHintFromRegistry(t *testing.T) {
+	hint := DefaultKeymap().Hint()
+
+	if !strings.HasPrefix(hint, "q h/? space r l") {
+		t.Fatalf("expected hint to start with core keys, got %q", hint)
+	}
+}
+
+func TestShouldOverrideBindingsFromKeymapFile(t *testing.T) {
+	keymap, err := ParseKeymap(strings.NewReader("# custom\nquit = ctrl-q, esc\nrestart = q\n"))
+	if err != nil {
+		t.Fatalf("expected keymap to parse, got %v", err)
+	}
+
+	if action, _ := keymap.Lookup(ContextGlobal, "q"); action != ActionRestart {
+		t.Fatalf("expected q rebound to restart, got %q", action)
+	}
+	if action, _ := keymap.Lookup(ContextGlobal, "ctrl-q"); action != ActionQuit {
+		t.Fatalf("expected ctrl-q bound to quit, got %q", action)
+	}
+	if _, ok := keymap.Lookup(ContextGlobal, "r"); ok {
+		t.Fatalf("expected r to be unbound after restart rebinding")
+	}
+}
+
+func TestShouldKeepCaseOfShiftedLettersAndNormalizeNamedKeys(t *testing.T) {
+	keymap, err := ParseKeymap(strings.NewReader("restart = G\nstep = n\nredo = Ctrl-Y\n"))
+	if err != nil {
+		t.Fatalf("expected keymap to parse, got %v", err)
+	}
+
+	if action, _ := keymap.Lookup(ContextGlobal, "G"); action != ActionRestart {
+		t.Fatalf("expected G bound to restart, got %q", action)
+	}
+	if _, ok := keymap.Lookup(ContextGlobal, "g"); ok {
+		t.Fatalf("expected g to stay unbound")
+	}
+	if action, _ := keymap.Lookup(ContextGlobal, "n"); action != ActionStep {
+		t.Fatalf("expected n bound to step, got %q", action)
+	}
+	if action, _ := keymap.Lookup(ContextGlobal, "ctrl-y"); action != ActionRedo {
+		t.Fatalf("expected Ctrl-Y to bind ctrl-y, got %q", action)
+	}
+}
+
+func TestShouldApplyVimPresetForCursorMovementInEditMode(t *testing.T) {
+	keymap, err := ParseKeymap(strings.NewReader("preset = vim\n"))
+	if err != nil {
+		t.Fatalf("expected preset to apply, got %v", err)
+	}
+	state := NewStateWithKeymap(keymap)
+
+	state.HandleKey("e")
+	state.HandleKey("l")
+	state.HandleKey("j")
+
+	if state.CursorX != 1 || state.CursorY != 1 {
+		t.Fatalf("expected hjkl cursor movement, got (%d,%d)", state.CursorX, state.CursorY)
+	}
+	if state.HandleKey("h"); state.HelpVisible {
+		t.Fatalf("expected h to move cursor rather than toggle help in vim edit mode")
+	}
+}
+
+func TestShouldReportKeymapErrorsWithLineNumbers(t *testing.T) {
+	cases := map[string]string{
+		"quit ctrl-q":     "line 1",
+		"\nfly = f":       "unknown action",
+		"preset = vscode": "unknown key preset",
+	}
+	for content, expected := range cases {
+		_, err := ParseKeymap(strings.NewReader(content))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error containing %q for %q, got %v", expected, content, err)
+		}
+	}
+}
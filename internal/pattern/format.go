@@ -0,0 +1,321 @@
+package pattern
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+const defaultRuleString = "B3/S23"
+
+// Pattern is a whole pattern file: the cells, where the board's top-left
+// corner sits in pattern coordinates, and the metadata the formats carry.
+// An empty Rule means the file did not name one.
+type Pattern struct {
+	Board    engine.Board
+	X        int
+	Y        int
+	Name     string
+	Author   string
+	Rule     string
+	Comments []string
+}
+
+// ParseFormat accepts the names and file extensions people use for each
+// format.
+func ParseFormat(name string) (PatternFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "rle":
+		return FormatRLE, nil
+	case "cells", "plaintext", "txt":
+		return FormatPlainText, nil
+	case "lif", "life", "life106", "life1.06":
+		return FormatLife106, nil
+	}
+	return "", fmt.Errorf("unknown pattern format %q (available: rle, cells, life106)", name)
+}
+
+func FormatFromPath(path string) (PatternFormat, bool) {
+	format, err := ParseFormat(filepath.Ext(path))
+	return format, err == nil
+}
+
+// Decode reads a pattern file in format, or in the format
+// SelectPreferredPattern picks when format is empty. The board is sized to
+// the pattern and the cells go through the ParseToBoard readers.
+func Decode(format PatternFormat, content string) (Pattern, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if format == "" {
+		detected, _, err := SelectPreferredPattern(content)
+		if err != nil {
+			return Pattern{}, err
+		}
+		format = detected
+	}
+	switch format {
+	case FormatRLE:
+		return decodeRLE(content)
+	case FormatPlainText:
+		return decodePlainText(content)
+	case FormatLife106:
+		return decodeLife106(content)
+	}
+	return Pattern{}, fmt.Errorf("unsupported format: %s", format)
+}
+
+func decodeRLE(content string) (Pattern, error) {
+	var p Pattern
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") || len(line) < 2 {
+			continue
+		}
+		text := strings.TrimSpace(line[2:])
+		switch line[1] {
+		case 'N':
+			p.Name = text
+		case 'O':
+			p.Author = text
+		case 'r':
+			p.Rule = text
+		case 'C', 'c':
+			if pos, ok := strings.CutPrefix(line, "#CXRLE"); ok {
+				p.X, p.Y = parseXRLEPos(pos)
+				continue
+			}
+			p.Comments = append(p.Comments, text)
+		}
+	}
+	body, ok := extractRLE(content)
+	if !ok {
+		return Pattern{}, fmt.Errorf("invalid RLE: missing header")
+	}
+	header := strings.SplitN(body, "\n", 2)[0]
+	width, height := 0, 0
+	for _, field := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(field, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "x":
+			width, err = strconv.Atoi(value)
+		case "y":
+			height, err = strconv.Atoi(value)
+		case "rule":
+			p.Rule = value
+		}
+		if err != nil || width < 0 || height < 0 {
+			return Pattern{}, fmt.Errorf("invalid RLE header: %q", header)
+		}
+	}
+	board, err := ParseToBoard(FormatRLE, body, width, height)
+	if err != nil {
+		return Pattern{}, err
+	}
+	p.Board = board
+	return p, nil
+}
+
+// parseXRLEPos reads Golly's "#CXRLE Pos=x,y" origin.
+func parseXRLEPos(text string) (int, int) {
+	for _, field := range strings.Fields(text) {
+		if value, ok := strings.CutPrefix(field, "Pos="); ok {
+			xs, ys, _ := strings.Cut(value, ",")
+			x, errX := strconv.Atoi(xs)
+			y, errY := strconv.Atoi(ys)
+			if errX == nil && errY == nil {
+				return x, y
+			}
+		}
+	}
+	return 0, 0
+}
+
+func decodePlainText(content string) (Pattern, error) {
+	var p Pattern
+	var rows []string
+	width := 0
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if text, ok := strings.CutPrefix(line, "!"); ok {
+			text = strings.TrimSpace(text)
+			switch {
+			case strings.HasPrefix(text, "Name:"):
+				p.Name = strings.TrimSpace(strings.TrimPrefix(text, "Name:"))
+			case strings.HasPrefix(text, "Author:"):
+				p.Author = strings.TrimSpace(strings.TrimPrefix(text, "Author:"))
+			case strings.HasPrefix(text, "Rule:"):
+				p.Rule = strings.TrimSpace(strings.TrimPrefix(text, "Rule:"))
+			case text != "":
+				p.Comments = append(p.Comments, text)
+			}
+			continue
+		}
+		// blank lines inside the grid are empty rows
+		if line == "" {
+			line = "."
+		}
+		rows = append(rows, line)
+		width = max(width, len(line))
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "." {
+		rows = rows[:len(rows)-1]
+	}
+	board, err := ParseToBoard(FormatPlainText, strings.Join(rows, "\n"), width, len(rows))
+	if err != nil {
+		return Pattern{}, err
+	}
+	p.Board = board
+	return p, nil
+}
+
+func decodeLife106(content string) (Pattern, error) {
+	var p Pattern
+	var cells []string
+	minX, minY, maxX, maxY := 0, 0, -1, -1
+	for index, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "#Life 1.06" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			text := strings.TrimSpace(line[min(2, len(line)):])
+			switch line[:min(2, len(line))] {
+			case "#N":
+				p.Name = text
+			case "#O":
+				p.Author = text
+			case "#R":
+				p.Rule = text
+			case "#D", "#C":
+				p.Comments = append(p.Comments, text)
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return Pattern{}, fmt.Errorf("invalid Life 1.06 coordinate on line %d: %q", index+1, line)
+		}
+		x, errX := strconv.Atoi(fields[0])
+		y, errY := strconv.Atoi(fields[1])
+		if errX != nil || errY != nil {
+			return Pattern{}, fmt.Errorf("invalid Life 1.06 coordinate on line %d: %q", index+1, line)
+		}
+		if len(cells) == 0 {
+			minX, minY, maxX, maxY = x, y, x, y
+		}
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+		cells = append(cells, line)
+	}
+	body := strings.Join(append([]string{"#Life 1.06"}, cells...), "\n")
+	board, err := parseLife106At(body, minX, minY, maxX-minX+1, maxY-minY+1)
+	if err != nil {
+		return Pattern{}, err
+	}
+	p.Board, p.X, p.Y = board, minX, minY
+	return p, nil
+}
+
+// Cropped trims the board to its live cells and moves the origin to match.
+func (p Pattern) Cropped() Pattern {
+	x, y, width, height := p.Board.BoundingBox()
+	p.Board = p.Board.Crop(x, y, width, height)
+	p.X += x
+	p.Y += y
+	return p
+}
+
+// Encode writes p in format. Metadata a format has no field for is kept as
+// comment lines the matching reader understands.
+func Encode(p Pattern, format PatternFormat) (string, error) {
+	switch format {
+	case FormatRLE:
+		return encodeRLE(p), nil
+	case FormatPlainText:
+		return encodePlainText(p), nil
+	case FormatLife106:
+		return encodeLife106(p), nil
+	}
+	return "", fmt.Errorf("unsupported format: %s", format)
+}
+
+func (p Pattern) rule() string {
+	if p.Rule == "" {
+		return defaultRuleString
+	}
+	return p.Rule
+}
+
+func encodeRLE(p Pattern) string {
+	var b strings.Builder
+	if p.Name != "" {
+		fmt.Fprintf(&b, "#N %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(&b, "#O %s\n", p.Author)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(&b, "#C %s\n", comment)
+	}
+	if p.X != 0 || p.Y != 0 {
+		fmt.Fprintf(&b, "#CXRLE Pos=%d,%d\n", p.X, p.Y)
+	}
+	fmt.Fprintf(&b, "x = %d, y = %d, rule = %s\n%s\n", p.Board.Width(), p.Board.Height(), p.rule(), rleBody(p.Board))
+	return b.String()
+}
+
+func encodePlainText(p Pattern) string {
+	var b strings.Builder
+	if p.Name != "" {
+		fmt.Fprintf(&b, "!Name: %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(&b, "!Author: %s\n", p.Author)
+	}
+	if p.Rule != "" {
+		fmt.Fprintf(&b, "!Rule: %s\n", p.Rule)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(&b, "!%s\n", comment)
+	}
+	for y := 0; y < p.Board.Height(); y++ {
+		for x := 0; x < p.Board.Width(); x++ {
+			if p.Board.IsAlive(x, y) {
+				b.WriteByte('O')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func encodeLife106(p Pattern) string {
+	var b strings.Builder
+	b.WriteString("#Life 1.06\n")
+	if p.Name != "" {
+		fmt.Fprintf(&b, "#N %s\n", p.Name)
+	}
+	if p.Author != "" {
+		fmt.Fprintf(&b, "#O %s\n", p.Author)
+	}
+	if p.Rule != "" {
+		fmt.Fprintf(&b, "#R %s\n", p.Rule)
+	}
+	for _, comment := range p.Comments {
+		fmt.Fprintf(&b, "#D %s\n", comment)
+	}
+	for y := 0; y < p.Board.Height(); y++ {
+		for x := 0; x < p.Board.Width(); x++ {
+			if p.Board.IsAlive(x, y) {
+				fmt.Fprintf(&b, "%d %d\n", p.X+x, p.Y+y)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package pattern
+
+import (
+	"strings"
+	"testing"
+)
+
+const gliderRLE = `#N Glider
+#O Richard K. Guy
+#C The smallest, most common spaceship.
+#C www.conwaylife.com/wiki/Glider
+x = 3, y = 3, rule = B3/S23
+bo$2bo$3o!
+`
+
+func TestShouldKeepMetadataAcrossEveryFormat(t *testing.T) {
+	glider, err := Decode(FormatRLE, gliderRLE)
+	if err != nil {
+		t.Fatalf("expected RLE to decode, got %v", err)
+	}
+	if glider.Name != "Glider" || glider.Author != "Richard K. Guy" || len(glider.Comments) != 2 || glider.Rule != "B3/S23" {
+		t.Fatalf("unexpected metadata %+v", glider)
+	}
+
+	for _, format := range []PatternFormat{FormatRLE, FormatPlainText, FormatLife106} {
+		encoded, err := Encode(glider, format)
+		if err != nil {
+			t.Fatalf("expected %s to encode, got %v", format, err)
+		}
+		decoded, err := Decode(format, encoded)
+		if err != nil {
+			t.Fatalf("expected %s to decode back, got %v\n%s", format, err, encoded)
+		}
+		if decoded.Name != glider.Name || decoded.Author != glider.Author || decoded.Rule != glider.Rule ||
+			strings.Join(decoded.Comments, "|") != strings.Join(glider.Comments, "|") {
+			t.Fatalf("%s lost metadata: %+v\n%s", format, decoded, encoded)
+		}
+		if EncodeRLE(decoded.Board) != EncodeRLE(glider.Board) {
+			t.Fatalf("%s changed the cells:\n%s", format, encoded)
+		}
+	}
+}
+
+func TestShouldWritePlainTextAndLife106(t *testing.T) {
+	glider, _ := Decode(FormatRLE, "x = 3, y = 3, rule = B36/S23\nbo$2bo$3o!\n")
+
+	cells, _ := Encode(glider, FormatPlainText)
+	if cells != "!Rule: B36/S23\n.O.\n..O\nOOO\n" {
+		t.Fatalf("unexpected PlainText %q", cells)
+	}
+	glider.X, glider.Y = -1, -1
+	life, _ := Encode(glider, FormatLife106)
+	if life != "#Life 1.06\n#R B36/S23\n0 -1\n1 0\n-1 1\n0 1\n1 1\n" {
+		t.Fatalf("unexpected Life 1.06 %q", life)
+	}
+}
+
+func TestShouldPlaceNegativeLife106CoordinatesAndCrop(t *testing.T) {
+	p, err := Decode(FormatLife106, "#Life 1.06\n-5 -2\n-4 -2\n-3 -2\n")
+	if err != nil {
+		t.Fatalf("expected Life 1.06 to decode, got %v", err)
+	}
+	if p.X != -5 || p.Y != -2 || p.Board.Width() != 3 || p.Board.Height() != 1 || p.Board.Population() != 3 {
+		t.Fatalf("unexpected blinker placement %+v", p)
+	}
+
+	rle, _ := Encode(p, FormatRLE)
+	if !strings.Contains(rle, "#CXRLE Pos=-5,-2\n") {
+		t.Fatalf("expected origin in RLE, got %q", rle)
+	}
+	back, _ := Decode(FormatRLE, rle)
+	if back.X != -5 || back.Y != -2 {
+		t.Fatalf("expected origin to survive RLE, got %d,%d", back.X, back.Y)
+	}
+}
+
+func TestShouldCropToBoundingBoxAndTreatBlankLinesAsEmptyRows(t *testing.T) {
+	p, err := Decode(FormatPlainText, "!Name: Two blocks\n......\n.OO\n.OO\n\n.OO\n.OO\n\n")
+	if err != nil {
+		t.Fatalf("expected PlainText to decode, got %v", err)
+	}
+	cropped := p.Cropped()
+
+	if cropped.X != 1 || cropped.Y != 1 || cropped.Board.Width() != 2 || cropped.Board.Height() != 5 || cropped.Board.Population() != 8 {
+		t.Fatalf("unexpected crop %dx%d@%d,%d", cropped.Board.Width(), cropped.Board.Height(), cropped.X, cropped.Y)
+	}
+}
+
+func TestShouldParseFormatNamesAndExtensions(t *testing.T) {
+	for name, want := range map[string]PatternFormat{"rle": FormatRLE, ".cells": FormatPlainText, "LIF": FormatLife106, "life106": FormatLife106} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Fatalf("expected %s for %q, got %s (%v)", want, name, got, err)
+		}
+	}
+	if format, ok := FormatFromPath("patterns/glider.cells"); !ok || format != FormatPlainText {
+		t.Fatalf("expected .cells to be PlainText")
+	}
+	if _, err := ParseFormat("mc"); err == nil {
+		t.Fatalf("expected unknown format to be rejected")
+	}
+}
@@ -0,0 +1,86 @@
+package pattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+const rleLineWidth = 70
+
+func EncodeRLE(board engine.Board) string {
+	return EncodeRLEWithRule(board, defaultRuleString)
+}
+
+func EncodeRLEWithRule(board engine.Board, rule string) string {
+	return fmt.Sprintf("x = %d, y = %d, rule = %s\n%s\n", board.Width(), board.Height(), rule, rleBody(board))
+}
+
+// RuleFromRLE returns the rule named in an RLE header, if any.
+func RuleFromRLE(content string) (string, bool) {
+	body, ok := extractRLE(strings.ReplaceAll(content, "\r\n", "\n"))
+	if !ok {
+		return "", false
+	}
+	header := strings.SplitN(body, "\n", 2)[0]
+	for _, field := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(field, "=")
+		if strings.TrimSpace(key) == "rule" && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+func rleBody(board engine.Board) string {
+	var body strings.Builder
+	lineLength := 0
+	emit := func(count int, tag byte) {
+		token := string(tag)
+		if count > 1 {
+			token = strconv.Itoa(count) + token
+		}
+		if lineLength+len(token) > rleLineWidth {
+			body.WriteByte('\n')
+			lineLength = 0
+		}
+		body.WriteString(token)
+		lineLength += len(token)
+	}
+
+	row := 0
+	for y := 0; y < board.Height(); y++ {
+		lastAlive := -1
+		for x := board.Width() - 1; x >= 0; x-- {
+			if board.IsAlive(x, y) {
+				lastAlive = x
+				break
+			}
+		}
+		if lastAlive < 0 {
+			continue
+		}
+		if y > row {
+			emit(y-row, '$')
+			row = y
+		}
+		x := 0
+		for x <= lastAlive {
+			alive := board.IsAlive(x, y)
+			run := 1
+			for x+run <= lastAlive && board.IsAlive(x+run, y) == alive {
+				run++
+			}
+			tag := byte('b')
+			if alive {
+				tag = 'o'
+			}
+			emit(run, tag)
+			x += run
+		}
+	}
+	emit(1, '!')
+	return body.String()
+}
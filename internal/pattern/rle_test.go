@@ -0,0 +1,57 @@
+package pattern
+
+import (
+	"strings"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldEncodeGliderAsRLE(t *testing.T) {
+	board := engine.NewBoard(3, 3)
+	board.SetAlive(1, 0, true)
+	board.SetAlive(2, 1, true)
+	board.SetAlive(0, 2, true)
+	board.SetAlive(1, 2, true)
+	board.SetAlive(2, 2, true)
+
+	encoded := EncodeRLE(board)
+
+	if encoded != "x = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n" {
+		t.Fatalf("unexpected RLE output %q", encoded)
+	}
+}
+
+func TestShouldWriteAndReadRuleInRLEHeader(t *testing.T) {
+	board := engine.NewBoard(2, 1)
+	board.SetAlive(0, 0, true)
+
+	encoded := EncodeRLEWithRule(board, "B36/S23")
+
+	if rule, ok := RuleFromRLE(encoded); !ok || rule != "B36/S23" {
+		t.Fatalf("expected rule B36/S23 in %q, got %q", encoded, rule)
+	}
+	if _, ok := RuleFromRLE("x = 1, y = 1\no!\n"); ok {
+		t.Fatalf("expected no rule without a rule field")
+	}
+}
+
+func TestShouldRoundTripRLEWithEmptyRows(t *testing.T) {
+	board := engine.NewBoard(5, 6)
+	board.SetAlive(4, 2, true)
+	board.SetAlive(0, 5, true)
+
+	encoded := EncodeRLE(board)
+	parsed, err := ParseToBoard(FormatRLE, strings.TrimSpace(encoded), 5, 6)
+	if err != nil {
+		t.Fatalf("expected encoded RLE to parse, got %v", err)
+	}
+
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 5; x++ {
+			if parsed.IsAlive(x, y) != board.IsAlive(x, y) {
+				t.Fatalf("round trip mismatch at (%d,%d) in %q", x, y, encoded)
+			}
+		}
+	}
+}
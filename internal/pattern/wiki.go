@@ -217,6 +217,12 @@ func parsePlainText(body string, width, height int) (engine.Board, error) {
 }
 
 func parseLife106(body string, width, height int) (engine.Board, error) {
+	return parseLife106At(body, 0, 0, width, height)
+}
+
+// parseLife106At places the cell at (originX, originY) on the board's
+// top-left corner.
+func parseLife106At(body string, originX, originY, width, height int) (engine.Board, error) {
 	lines := strings.Split(body, "\n")
 	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "#Life 1.06" {
 		return engine.Board{}, fmt.Errorf("invalid Life 1.06 header")
@@ -236,6 +242,8 @@ func parseLife106(body string, width, height int) (engine.Board, error) {
 		if err != nil {
 			return engine.Board{}, fmt.Errorf("invalid Life 1.06 y coordinate: %q", fields[1])
 		}
+		x -= originX
+		y -= originY
 		if x >= 0 && y >= 0 && x < width && y < height {
 			board.SetAlive(x, y, true)
 		}
@@ -0,0 +1,150 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/gif"
+	"image/png"
+	"io"
+	"time"
+
+	"gol-on-cli/internal/engine"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+func renderAnimationFrames(frames []engine.Board, options ImageOptions) []*image.Paletted {
+	images := make([]*image.Paletted, 0, len(frames))
+	for i, frame := range frames {
+		previous := &frames[i]
+		if i > 0 {
+			previous = &frames[i-1]
+		}
+		images = append(images, RenderImage(frame, previous, options))
+	}
+	return images
+}
+
+func EncodeGIF(w io.Writer, frames []engine.Board, options ImageOptions, delay time.Duration) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames to encode")
+	}
+	centiseconds := int(delay / (10 * time.Millisecond))
+	if centiseconds < 1 {
+		centiseconds = 1
+	}
+	anim := &gif.GIF{}
+	for _, img := range renderAnimationFrames(frames, options) {
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, centiseconds)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+func EncodeAPNG(w io.Writer, frames []engine.Board, options ImageOptions, delay time.Duration) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames to encode")
+	}
+	images := renderAnimationFrames(frames, options)
+	delayMillis := uint16(min(int(delay/time.Millisecond), 0xFFFF))
+
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+	sequence := uint32(0)
+	for i, img := range images {
+		chunks, err := encodePNGChunks(img)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			for _, chunk := range chunks {
+				if chunk.kind == "IHDR" || chunk.kind == "PLTE" {
+					if err := writePNGChunk(w, chunk.kind, chunk.data); err != nil {
+						return err
+					}
+				}
+			}
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:4], uint32(len(images)))
+			if err := writePNGChunk(w, "acTL", actl); err != nil {
+				return err
+			}
+		}
+
+		bounds := img.Bounds()
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:4], sequence)
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(bounds.Dy()))
+		binary.BigEndian.PutUint16(fctl[20:22], delayMillis)
+		binary.BigEndian.PutUint16(fctl[22:24], 1000)
+		if err := writePNGChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+		sequence++
+
+		for _, chunk := range chunks {
+			if chunk.kind != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				if err := writePNGChunk(w, "IDAT", chunk.data); err != nil {
+					return err
+				}
+				continue
+			}
+			fdat := make([]byte, 4+len(chunk.data))
+			binary.BigEndian.PutUint32(fdat[0:4], sequence)
+			copy(fdat[4:], chunk.data)
+			if err := writePNGChunk(w, "fdAT", fdat); err != nil {
+				return err
+			}
+			sequence++
+		}
+	}
+	return writePNGChunk(w, "IEND", nil)
+}
+
+type pngChunk struct {
+	kind string
+	data []byte
+}
+
+func encodePNGChunks(img image.Image) ([]pngChunk, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	raw := buf.Bytes()[len(pngSignature):]
+	chunks := make([]pngChunk, 0)
+	for len(raw) >= 12 {
+		length := int(binary.BigEndian.Uint32(raw[0:4]))
+		if len(raw) < 12+length {
+			return nil, fmt.Errorf("truncated png chunk")
+		}
+		chunks = append(chunks, pngChunk{kind: string(raw[4:8]), data: raw[8 : 8+length]})
+		raw = raw[12+length:]
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(w io.Writer, kind string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], kind)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:8])
+	crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package renderer
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldEncodeAnimatedGIFWithFrameDelayAndCellSize(t *testing.T) {
+	frames := blinkerFrames()
+	var buf bytes.Buffer
+
+	err := EncodeGIF(&buf, frames, ImageOptions{CellSize: 4, Palette: SelectPalette(true)}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected gif encoding to succeed, got %v", err)
+	}
+
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("expected valid gif, got %v", err)
+	}
+	if len(decoded.Image) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(decoded.Image))
+	}
+	if decoded.Delay[0] != 20 {
+		t.Fatalf("expected 20cs delay, got %d", decoded.Delay[0])
+	}
+	if decoded.Config.Width != 12 || decoded.Config.Height != 12 {
+		t.Fatalf("expected 12x12 image, got %dx%d", decoded.Config.Width, decoded.Config.Height)
+	}
+}
+
+func TestShouldEncodeAPNGReadableAsPNGWithAnimationControl(t *testing.T) {
+	frames := blinkerFrames()
+	var buf bytes.Buffer
+
+	if err := EncodeAPNG(&buf, frames, ImageOptions{CellSize: 2, GridLines: true, Palette: SelectPalette(false)}, 100*time.Millisecond); err != nil {
+		t.Fatalf("expected apng encoding to succeed, got %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("acTL")) || !bytes.Contains(buf.Bytes(), []byte("fdAT")) {
+		t.Fatalf("expected animation chunks in apng output")
+	}
+	if _, err := png.Decode(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("expected default frame to decode as png, got %v", err)
+	}
+}
+
+func TestShouldDrawFirstAnimationFrameWithoutNewbornCells(t *testing.T) {
+	frames := blinkerFrames()
+
+	images := renderAnimationFrames(frames, ImageOptions{CellSize: 1, Palette: SelectPalette(true)})
+
+	if index := images[0].ColorIndexAt(1, 1); index != imageIndexAlive {
+		t.Fatalf("expected a live cell in the first frame to use the alive color, got index %d", index)
+	}
+	if index := images[1].ColorIndexAt(1, 0); index != imageIndexNewborn {
+		t.Fatalf("expected a cell born in the second frame to use the newborn color, got index %d", index)
+	}
+}
+
+func TestShouldRejectEmptyAnimation(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := EncodeGIF(&buf, nil, ImageOptions{}, time.Second); err == nil {
+		t.Fatalf("expected error for empty animation")
+	}
+}
+
+func blinkerFrames() []engine.Board {
+	first := engine.NewBoard(3, 3)
+	for x := 0; x < 3; x++ {
+		first.SetAlive(x, 1, true)
+	}
+	return []engine.Board{first, first.NextGeneration()}
+}
@@ -0,0 +1,36 @@
+package renderer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// BuildHelpOverlay lays out lines inside a box no larger than width x height,
+// titled title and scrolled by offset. It returns the box rows and the offset
+// after clamping.
+func BuildHelpOverlay(title string, lines []string, width, height, offset int) ([]string, int) {
+	if width < 4 || height < 3 {
+		return nil, 0
+	}
+	inner := utf8.RuneCountInString(title)
+	for _, line := range lines {
+		inner = max(inner, utf8.RuneCountInString(line)+2)
+	}
+	inner = min(inner, width-2)
+	visible := min(len(lines), height-2)
+	offset = max(0, min(offset, len(lines)-visible))
+
+	rows := make([]string, 0, visible+2)
+	title = TruncateColumns(title, inner)
+	rows = append(rows, "┌"+title+strings.Repeat("─", inner-utf8.RuneCountInString(title))+"┐")
+	for _, line := range lines[offset : offset+visible] {
+		text := TruncateColumns(" "+line, inner)
+		rows = append(rows, "│"+text+strings.Repeat(" ", inner-utf8.RuneCountInString(text))+"│")
+	}
+	footer := ""
+	if offset+visible < len(lines) && inner > 7 {
+		footer = " more… "
+	}
+	rows = append(rows, "└"+footer+strings.Repeat("─", inner-utf8.RuneCountInString(footer))+"┘")
+	return rows, offset
+}
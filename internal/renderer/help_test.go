@@ -0,0 +1,38 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShouldBoxHelpLinesWithinScreen(t *testing.T) {
+	lines := []string{"q  quit  Quit", "r  restart  Restart", "n  step  Step"}
+
+	rows, offset := BuildHelpOverlay(" Help — esc close ", lines, 80, 10, 0)
+
+	if offset != 0 || len(rows) != 5 {
+		t.Fatalf("expected 3 lines plus borders, got %d rows", len(rows))
+	}
+	for _, row := range rows {
+		if utf8.RuneCountInString(row) != utf8.RuneCountInString(rows[0]) {
+			t.Fatalf("expected rectangular box, got %q", rows)
+		}
+	}
+	assertContains(t, rows[0], "esc close")
+	assertContains(t, rows[1], "quit")
+}
+
+func TestShouldScrollHelpAndClampOffset(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", i+1)
+	}
+
+	rows, offset := BuildHelpOverlay(" Help ", lines, 60, 7, 100)
+
+	if offset != 15 || len(rows) != 7 {
+		t.Fatalf("expected offset clamped to 15 with 5 visible lines, got %d and %d rows", offset, len(rows))
+	}
+	assertContains(t, rows[5], strings.Repeat("x", 20))
+}
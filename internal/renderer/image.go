@@ -0,0 +1,137 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+
+	"gol-on-cli/internal/engine"
+)
+
+const (
+	imageIndexDead uint8 = iota
+	imageIndexAlive
+	imageIndexNewborn
+	imageIndexRecentlyDead
+	imageIndexGrid
+)
+
+type ImageOptions struct {
+	CellSize  int
+	GridLines bool
+	Palette   Palette
+}
+
+func (o ImageOptions) cellSize() int {
+	if o.CellSize <= 0 {
+		return 1
+	}
+	return o.CellSize
+}
+
+func ImageColorPalette(palette Palette) color.Palette {
+	dead := PaletteRGBA(palette, palette.Dead)
+	return color.Palette{
+		dead,
+		PaletteRGBA(palette, palette.Alive),
+		PaletteRGBA(palette, palette.Newborn),
+		PaletteRGBA(palette, palette.RecentlyDead),
+		gridColor(dead),
+	}
+}
+
+func RenderImage(board engine.Board, previous *engine.Board, options ImageOptions) *image.Paletted {
+	size := options.cellSize()
+	bounds := image.Rect(0, 0, board.Width()*size, board.Height()*size)
+	img := image.NewPaletted(bounds, ImageColorPalette(options.Palette))
+	for y := 0; y < board.Height(); y++ {
+		for x := 0; x < board.Width(); x++ {
+			index := cellColorIndex(board.IsAlive(x, y), previous != nil && previous.IsAlive(x, y))
+			if index == imageIndexDead {
+				continue
+			}
+			fillCell(img, x, y, size, index)
+		}
+	}
+	if options.GridLines && size > 1 {
+		drawGrid(img, board.Width(), board.Height(), size)
+	}
+	return img
+}
+
+func cellColorIndex(isAlive, wasAlive bool) uint8 {
+	if isAlive {
+		if !wasAlive {
+			return imageIndexNewborn
+		}
+		return imageIndexAlive
+	}
+	if wasAlive {
+		return imageIndexRecentlyDead
+	}
+	return imageIndexDead
+}
+
+func fillCell(img *image.Paletted, x, y, size int, index uint8) {
+	for py := y * size; py < (y+1)*size; py++ {
+		for px := x * size; px < (x+1)*size; px++ {
+			img.SetColorIndex(px, py, index)
+		}
+	}
+}
+
+func drawGrid(img *image.Paletted, width, height, size int) {
+	bounds := img.Bounds()
+	for x := 0; x <= width; x++ {
+		px := min(x*size, bounds.Max.X-1)
+		for py := 0; py < bounds.Max.Y; py++ {
+			img.SetColorIndex(px, py, imageIndexGrid)
+		}
+	}
+	for y := 0; y <= height; y++ {
+		py := min(y*size, bounds.Max.Y-1)
+		for px := 0; px < bounds.Max.X; px++ {
+			img.SetColorIndex(px, py, imageIndexGrid)
+		}
+	}
+}
+
+func gridColor(dead color.RGBA) color.RGBA {
+	lift := func(v uint8) uint8 {
+		if v > 255-24 {
+			return 255
+		}
+		return v + 24
+	}
+	return color.RGBA{R: lift(dead.R), G: lift(dead.G), B: lift(dead.B), A: 255}
+}
+
+func PaletteRGBA(palette Palette, value string) color.RGBA {
+	if rgb, ok := parseHexColor(value); ok {
+		return color.RGBA{R: uint8(rgb[0]), G: uint8(rgb[1]), B: uint8(rgb[2]), A: 255}
+	}
+	if index, err := strconv.Atoi(value); err == nil && index >= 0 && index < 256 {
+		return xtermColor(index)
+	}
+	return color.RGBA{A: 255}
+}
+
+func xtermColor(index int) color.RGBA {
+	base := [16][3]uint8{
+		{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+		{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+		{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	switch {
+	case index < 16:
+		return color.RGBA{R: base[index][0], G: base[index][1], B: base[index][2], A: 255}
+	case index < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		cube := index - 16
+		return color.RGBA{R: levels[cube/36], G: levels[(cube/6)%6], B: levels[cube%6], A: 255}
+	default:
+		gray := uint8(8 + (index-232)*10)
+		return color.RGBA{R: gray, G: gray, B: gray, A: 255}
+	}
+}
@@ -3,6 +3,7 @@ package renderer
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"gol-on-cli/internal/engine"
 )
@@ -27,8 +28,35 @@ type StatusBarData struct {
 	Paused        bool
 	PatternSource string
 	Notice        string
+	KeyHint       string
+	EditMode      string
+	TimelineIndex int
+	TimelineSize  int
+	GensPerSecond float64
+	StepSize      int
+	MaxSpeed      bool
+	Summary       *BoardSummary
+	Rule          string
+	Topology      string
+	FPS           int
+	PatternName   string
+	Cycle         string
+	SoupID        string
 }
 
+type BoardSummary struct {
+	Population int
+	BoxX       int
+	BoxY       int
+	BoxWidth   int
+	BoxHeight  int
+}
+
+const timelineScrubberWidth = 12
+
+// defaultKeyHint is shown when the caller has no keymap to describe.
+const defaultKeyHint = "q h/? space r l"
+
 func SelectPalette(supportsTrueColor bool) Palette {
 	if supportsTrueColor {
 		return Palette{Mode: ModeTrueColor, Alive: "#00FF87", Dead: "#1F2937", Newborn: "#FFD700", RecentlyDead: "#FF6347"}
@@ -36,21 +64,150 @@ func SelectPalette(supportsTrueColor bool) Palette {
 	return Palette{Mode: ModeFallback, Alive: "46", Dead: "236", Newborn: "220", RecentlyDead: "203"}
 }
 
-func BuildStatusBar(data StatusBarData) string {
+type StatusSegment struct {
+	Text     string
+	Priority int
+}
+
+// StatusSegments lists the status bar pieces in display order. Lower
+// priorities are more important and survive longest on narrow terminals.
+func StatusSegments(data StatusBarData) []StatusSegment {
 	state := "running"
 	if data.Paused {
 		state = "paused"
 	}
-	status := fmt.Sprintf(
-		"gen:%d | state:%s | source:%s | keys:q h/? space r l",
-		data.Generation,
-		state,
-		data.PatternSource,
+	keys := data.KeyHint
+	if keys == "" {
+		keys = defaultKeyHint
+	}
+	segments := []StatusSegment{
+		{Text: fmt.Sprintf("gen:%d", data.Generation), Priority: 0},
+		{Text: "state:" + state, Priority: 0},
+	}
+	if data.Summary != nil {
+		segments = append(segments, StatusSegment{Text: fmt.Sprintf("pop:%d", data.Summary.Population), Priority: 2})
+		if data.Summary.BoxWidth > 0 {
+			segments = append(segments, StatusSegment{Text: fmt.Sprintf("box:%dx%d@%d,%d", data.Summary.BoxWidth, data.Summary.BoxHeight, data.Summary.BoxX, data.Summary.BoxY), Priority: 5})
+		}
+	}
+	if data.Cycle != "" {
+		segments = append(segments, StatusSegment{Text: "cycle:" + data.Cycle, Priority: 3})
+	}
+	if data.Rule != "" {
+		segments = append(segments, StatusSegment{Text: "rule:" + data.Rule, Priority: 4})
+	}
+	if data.Topology != "" {
+		segments = append(segments, StatusSegment{Text: "topology:" + data.Topology, Priority: 6})
+	}
+	if data.FPS > 0 {
+		segments = append(segments, StatusSegment{Text: fmt.Sprintf("fps:%d", data.FPS), Priority: 4})
+	}
+	if data.SoupID != "" {
+		segments = append(segments, StatusSegment{Text: "soup:" + data.SoupID, Priority: 4})
+	}
+	if data.PatternName != "" {
+		segments = append(segments, StatusSegment{Text: "pattern:" + data.PatternName, Priority: 2})
+	}
+	segments = append(segments,
+		StatusSegment{Text: "source:" + data.PatternSource, Priority: 7},
+		StatusSegment{Text: "keys:" + keys, Priority: 8},
 	)
-	if data.Notice == "" {
-		return status
+	if speed := buildSpeedSegment(data); speed != "" {
+		segments = append(segments, StatusSegment{Text: "speed:" + speed, Priority: 3})
+	}
+	if data.TimelineSize > 1 && (data.Paused || data.TimelineIndex < data.TimelineSize-1) {
+		segments = append(segments, StatusSegment{Text: "timeline:" + BuildTimelineScrubber(data.TimelineIndex, data.TimelineSize, timelineScrubberWidth), Priority: 3})
+	}
+	if data.EditMode != "" {
+		segments = append(segments, StatusSegment{Text: "edit:" + data.EditMode, Priority: 1})
 	}
-	return fmt.Sprintf("%s | notice:%s", status, data.Notice)
+	if data.Notice != "" {
+		segments = append(segments, StatusSegment{Text: "notice:" + data.Notice, Priority: 1})
+	}
+	return segments
+}
+
+func BuildStatusBar(data StatusBarData) string {
+	return joinSegments(StatusSegments(data))
+}
+
+// FitStatusBar drops the least important segments until the bar fits in
+// width columns, truncating the remainder if even the essentials overflow.
+func FitStatusBar(data StatusBarData, width int) string {
+	segments := StatusSegments(data)
+	for utf8.RuneCountInString(joinSegments(segments)) > width {
+		drop := -1
+		for i, segment := range segments {
+			if segment.Priority > 0 && (drop < 0 || segment.Priority >= segments[drop].Priority) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		segments = append(segments[:drop], segments[drop+1:]...)
+	}
+	return TruncateColumns(joinSegments(segments), width)
+}
+
+func joinSegments(segments []StatusSegment) string {
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		parts[i] = segment.Text
+	}
+	return strings.Join(parts, " | ")
+}
+
+func TruncateColumns(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func buildSpeedSegment(data StatusBarData) string {
+	if data.GensPerSecond <= 0 && data.StepSize <= 1 && !data.MaxSpeed {
+		return ""
+	}
+	segment := fmt.Sprintf("%.1fgen/s", data.GensPerSecond)
+	if data.StepSize > 1 {
+		segment = fmt.Sprintf("%s x%d", segment, data.StepSize)
+	}
+	if data.MaxSpeed {
+		segment += " max"
+	}
+	return segment
+}
+
+func BuildTimelineScrubber(index, size, width int) string {
+	if size <= 0 || width <= 0 {
+		return ""
+	}
+	marker := 0
+	if size > 1 {
+		marker = index * (width - 1) / (size - 1)
+	}
+	var b strings.Builder
+	b.WriteRune('[')
+	for i := 0; i < width; i++ {
+		switch {
+		case i == marker:
+			b.WriteRune('|')
+		case i < marker:
+			b.WriteRune('=')
+		default:
+			b.WriteRune('-')
+		}
+	}
+	b.WriteRune(']')
+	if behind := size - 1 - index; behind > 0 {
+		fmt.Fprintf(&b, "-%d", behind)
+	}
+	return b.String()
 }
 
 func BuildFrame(board engine.Board, status StatusBarData) string {
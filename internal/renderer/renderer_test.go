@@ -3,6 +3,7 @@ package renderer
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"gol-on-cli/internal/engine"
 )
@@ -92,3 +93,51 @@ func assertContains(t *testing.T, got, expected string) {
 		t.Fatalf("expected %q to contain %q", got, expected)
 	}
 }
+
+func TestShouldShowTimelineScrubberWhenRewound(t *testing.T) {
+	status := BuildStatusBar(StatusBarData{Generation: 5, PatternSource: "random", TimelineIndex: 5, TimelineSize: 11})
+
+	assertContains(t, status, "timeline:[=====|------]-5")
+}
+
+func TestShouldHideTimelineScrubberAtLiveHeadWhileRunning(t *testing.T) {
+	status := BuildStatusBar(StatusBarData{Generation: 10, PatternSource: "random", TimelineIndex: 10, TimelineSize: 11})
+
+	if strings.Contains(status, "timeline:") {
+		t.Fatalf("expected no scrubber at live head, got %q", status)
+	}
+}
+
+func TestShouldShowEffectiveSpeedInStatusBar(t *testing.T) {
+	status := BuildStatusBar(StatusBarData{PatternSource: "random", GensPerSecond: 120, StepSize: 4, MaxSpeed: true})
+
+	assertContains(t, status, "speed:120.0gen/s x4 max")
+}
+
+func TestShouldShowBoardSummaryRuleAndPatternInStatusBar(t *testing.T) {
+	summary := &BoardSummary{Population: 2, BoxX: 1, BoxY: 1, BoxWidth: 3, BoxHeight: 2}
+
+	status := BuildStatusBar(StatusBarData{PatternSource: "random", Summary: summary, Rule: "B3/S23", Topology: "torus", FPS: 10, PatternName: "Glider", Cycle: "osc p2"})
+
+	for _, expected := range []string{"cycle:osc p2", "pop:2", "box:3x2@1,1", "rule:B3/S23", "topology:torus", "fps:10", "pattern:Glider"} {
+		assertContains(t, status, expected)
+	}
+}
+
+func TestShouldDropLowPrioritySegmentsToFitWidth(t *testing.T) {
+	data := StatusBarData{Generation: 12, PatternSource: "random", Summary: &BoardSummary{Population: 9}, Rule: "B3/S23", Topology: "torus", Notice: "saved"}
+
+	status := FitStatusBar(data, 40)
+
+	if utf8.RuneCountInString(status) > 40 {
+		t.Fatalf("expected status within 40 columns, got %q", status)
+	}
+	assertContains(t, status, "gen:12")
+	assertContains(t, status, "notice:saved")
+	if strings.Contains(status, "keys:") {
+		t.Fatalf("expected key hints dropped first, got %q", status)
+	}
+	if got := FitStatusBar(data, 8); utf8.RuneCountInString(got) != 8 {
+		t.Fatalf("expected essentials truncated to width, got %q", got)
+	}
+}
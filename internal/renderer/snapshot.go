@@ -0,0 +1,57 @@
+package renderer
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+
+	"gol-on-cli/internal/engine"
+)
+
+func EncodePNG(w io.Writer, board engine.Board, previous *engine.Board, options ImageOptions) error {
+	return png.Encode(w, RenderImage(board, previous, options))
+}
+
+func EncodeSVG(w io.Writer, board engine.Board, previous *engine.Board, options ImageOptions) error {
+	size := options.cellSize()
+	colors := ImageColorPalette(options.Palette)
+	width := board.Width() * size
+	height := board.Height() * size
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, svgColor(colors[imageIndexDead]))
+	for y := 0; y < board.Height(); y++ {
+		x := 0
+		for x < board.Width() {
+			index := cellColorIndex(board.IsAlive(x, y), previous != nil && previous.IsAlive(x, y))
+			run := 1
+			for x+run < board.Width() && cellColorIndex(board.IsAlive(x+run, y), previous != nil && previous.IsAlive(x+run, y)) == index {
+				run++
+			}
+			if index != imageIndexDead {
+				fmt.Fprintf(out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x*size, y*size, run*size, size, svgColor(colors[index]))
+			}
+			x += run
+		}
+	}
+	if options.GridLines && size > 1 {
+		fmt.Fprintf(out, `<g stroke="%s" stroke-width="1">`+"\n", svgColor(colors[imageIndexGrid]))
+		for x := 0; x <= board.Width(); x++ {
+			fmt.Fprintf(out, `<line x1="%d" y1="0" x2="%d" y2="%d"/>`+"\n", x*size, x*size, height)
+		}
+		for y := 0; y <= board.Height(); y++ {
+			fmt.Fprintf(out, `<line x1="0" y1="%d" x2="%d" y2="%d"/>`+"\n", y*size, width, y*size)
+		}
+		fmt.Fprintln(out, "</g>")
+	}
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
+
+func svgColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02X%02X%02X", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,61 @@
+package renderer
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldMergeHorizontalRunsIntoSingleSVGRect(t *testing.T) {
+	board := engine.NewBoard(5, 1)
+	for x := 0; x < 4; x++ {
+		board.SetAlive(x, 0, true)
+	}
+	var buf bytes.Buffer
+
+	if err := EncodeSVG(&buf, board, &board, ImageOptions{CellSize: 10, Palette: SelectPalette(true)}); err != nil {
+		t.Fatalf("expected svg encoding to succeed, got %v", err)
+	}
+
+	svg := buf.String()
+	assertContains(t, svg, `<rect x="0" y="0" width="40" height="10" fill="#00FF87"/>`)
+	if strings.Count(svg, "<rect") != 2 {
+		t.Fatalf("expected background plus one merged rect, got %q", svg)
+	}
+}
+
+func TestShouldUseNewbornAndRecentlyDeadColorsInSVG(t *testing.T) {
+	previous := engine.NewBoard(2, 1)
+	previous.SetAlive(1, 0, true)
+	current := engine.NewBoard(2, 1)
+	current.SetAlive(0, 0, true)
+	var buf bytes.Buffer
+
+	if err := EncodeSVG(&buf, current, &previous, ImageOptions{Palette: SelectPalette(true)}); err != nil {
+		t.Fatalf("expected svg encoding to succeed, got %v", err)
+	}
+
+	assertContains(t, buf.String(), "#FFD700")
+	assertContains(t, buf.String(), "#FF6347")
+}
+
+func TestShouldEncodePNGWithCellSizedDimensions(t *testing.T) {
+	board := engine.NewBoard(3, 2)
+	board.SetAlive(1, 1, true)
+	var buf bytes.Buffer
+
+	if err := EncodePNG(&buf, board, nil, ImageOptions{CellSize: 5, Palette: SelectPalette(false)}); err != nil {
+		t.Fatalf("expected png encoding to succeed, got %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("expected valid png, got %v", err)
+	}
+	if img.Bounds().Dx() != 15 || img.Bounds().Dy() != 10 {
+		t.Fatalf("expected 15x10 png, got %v", img.Bounds())
+	}
+}
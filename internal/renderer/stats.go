@@ -0,0 +1,107 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	StatsPanelWidth = 26
+	sparklineRows   = 4
+)
+
+// Braille dot bits for the left and right column of a cell, bottom to top.
+var (
+	brailleLeft  = [4]rune{0x40, 0x04, 0x02, 0x01}
+	brailleRight = [4]rune{0x80, 0x20, 0x10, 0x08}
+)
+
+// BuildSparkline draws the most recent values as a braille bar graph that is
+// width characters wide and rows characters tall (two samples per character,
+// four dot levels per row).
+func BuildSparkline(values []int, width, rows int) []string {
+	if width <= 0 || rows <= 0 {
+		return nil
+	}
+	samples := values
+	if len(samples) > width*2 {
+		samples = samples[len(samples)-width*2:]
+	}
+	peak := 0
+	for _, value := range samples {
+		peak = max(peak, value)
+	}
+	levels := rows * 4
+	heights := make([]int, width*2)
+	offset := width*2 - len(samples)
+	for i, value := range samples {
+		if peak > 0 && value > 0 {
+			heights[offset+i] = max(1, value*levels/peak)
+		}
+	}
+
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		base := (rows - 1 - row) * 4
+		var b strings.Builder
+		for col := 0; col < width; col++ {
+			cell := rune(0x2800)
+			for dot := 0; dot < 4; dot++ {
+				if heights[col*2] > base+dot {
+					cell |= brailleLeft[dot]
+				}
+				if heights[col*2+1] > base+dot {
+					cell |= brailleRight[dot]
+				}
+			}
+			b.WriteRune(cell)
+		}
+		lines[row] = b.String()
+	}
+	return lines
+}
+
+// StatsPanelData is what the statistics panel shows for one generation.
+type StatsPanelData struct {
+	BoardSummary
+	Generation int
+	Births     int
+	Deaths     int
+	Density    float64
+}
+
+// BuildStatsPanel lays out the side panel rows, each padded to
+// StatsPanelWidth columns.
+func BuildStatsPanel(stats StatsPanelData, history []int) []string {
+	inner := StatsPanelWidth - 2
+	rows := []string{
+		"Statistics",
+		statsRow("generation", fmt.Sprintf("%d", stats.Generation), inner),
+		statsRow("population", fmt.Sprintf("%d", stats.Population), inner),
+		statsRow("births", fmt.Sprintf("%d", stats.Births), inner),
+		statsRow("deaths", fmt.Sprintf("%d", stats.Deaths), inner),
+		statsRow("density", fmt.Sprintf("%.2f%%", stats.Density*100), inner),
+		statsRow("box", fmt.Sprintf("%dx%d@%d,%d", stats.BoxWidth, stats.BoxHeight, stats.BoxX, stats.BoxY), inner),
+		"",
+		"population history",
+	}
+	rows = append(rows, BuildSparkline(history, inner, sparklineRows)...)
+	if len(history) > 0 {
+		low, high := history[0], history[0]
+		for _, value := range history {
+			low, high = min(low, value), max(high, value)
+		}
+		rows = append(rows, statsRow("min/max", fmt.Sprintf("%d/%d", low, high), inner))
+	}
+	for i, row := range rows {
+		row = TruncateColumns(row, inner)
+		rows[i] = " " + row + strings.Repeat(" ", inner-utf8.RuneCountInString(row)) + " "
+	}
+	return rows
+}
+
+func statsRow(label, value string, width int) string {
+	gap := max(1, width-utf8.RuneCountInString(label)-utf8.RuneCountInString(value))
+	return label + strings.Repeat(" ", gap) + value
+}
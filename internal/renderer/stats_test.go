@@ -0,0 +1,39 @@
+package renderer
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShouldDrawBrailleSparklineScaledToPeak(t *testing.T) {
+	lines := BuildSparkline([]int{0, 4, 8, 2}, 2, 1)
+
+	if len(lines) != 1 {
+		t.Fatalf("expected one row, got %d", len(lines))
+	}
+	// heights 0,2 | 4,1 out of four dot levels
+	if lines[0] != "⢠⣇" {
+		t.Fatalf("unexpected sparkline %q", lines[0])
+	}
+}
+
+func TestShouldRightAlignShortHistoryInSparkline(t *testing.T) {
+	lines := BuildSparkline([]int{5}, 3, 2)
+
+	if []rune(lines[0])[0] != 0x2800 || []rune(lines[1])[2] == 0x2800 {
+		t.Fatalf("expected latest sample at the right edge, got %q", lines)
+	}
+}
+
+func TestShouldLayOutStatsPanelAtFixedWidth(t *testing.T) {
+	rows := BuildStatsPanel(StatsPanelData{BoardSummary: BoardSummary{Population: 10}, Generation: 42, Births: 3, Deaths: 1, Density: 0.125}, []int{8, 9, 10})
+
+	for _, row := range rows {
+		if utf8.RuneCountInString(row) != StatsPanelWidth {
+			t.Fatalf("expected every row to be %d columns, got %q", StatsPanelWidth, row)
+		}
+	}
+	assertContains(t, rows[2], "population")
+	assertContains(t, rows[5], "12.50%")
+	assertContains(t, rows[len(rows)-1], "8/10")
+}
@@ -0,0 +1,199 @@
+package renderer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/engine"
+)
+
+var fallbackFadeRamp = []int{46, 40, 34, 28, 22, 238}
+
+var fallbackTrailRamp = []int{203, 167, 131, 95, 59, 238}
+
+type TrailPoint struct {
+	X int
+	Y int
+}
+
+type Trail struct {
+	length int
+	width  int
+	height int
+	alive  []bool
+	ages   []int
+}
+
+func NewTrail(length, width, height int) *Trail {
+	t := &Trail{length: length}
+	t.Reset(width, height)
+	return t
+}
+
+func (t *Trail) Length() int {
+	return t.length
+}
+
+func (t *Trail) Reset(width, height int) {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	t.width = width
+	t.height = height
+	t.alive = make([]bool, width*height)
+	t.ages = make([]int, width*height)
+}
+
+func (t *Trail) Seed(board engine.Board) {
+	t.Reset(board.Width(), board.Height())
+	for y := 0; y < t.height; y++ {
+		for x := 0; x < t.width; x++ {
+			t.alive[y*t.width+x] = board.IsAlive(x, y)
+		}
+	}
+}
+
+func (t *Trail) Advance(board engine.Board) []TrailPoint {
+	if board.Width() != t.width || board.Height() != t.height {
+		t.Seed(board)
+		return nil
+	}
+	changed := make([]TrailPoint, 0)
+	for y := 0; y < t.height; y++ {
+		for x := 0; x < t.width; x++ {
+			index := y*t.width + x
+			isAlive := board.IsAlive(x, y)
+			age := t.ages[index]
+			switch {
+			case isAlive:
+				age = 0
+			case t.alive[index]:
+				age = 1
+			case age > 0 && age < t.length:
+				age++
+			default:
+				age = 0
+			}
+			t.alive[index] = isAlive
+			if age != t.ages[index] {
+				t.ages[index] = age
+				changed = append(changed, TrailPoint{X: x, Y: y})
+			}
+		}
+	}
+	return changed
+}
+
+func (t *Trail) Age(x, y int) int {
+	if t == nil || x < 0 || y < 0 || x >= t.width || y >= t.height {
+		return 0
+	}
+	return t.ages[y*t.width+x]
+}
+
+func TrailColor(palette Palette, age, length int) string {
+	if age <= 0 || length <= 0 {
+		return palette.Dead
+	}
+	if age > length {
+		age = length
+	}
+	switch palette.Mode {
+	case ModeTrueColor:
+		return mixHexColor(palette.RecentlyDead, palette.Dead, float64(age-1)/float64(length))
+	case ModeFallback:
+		step := (age - 1) * len(fallbackTrailRamp) / length
+		return strconv.Itoa(fallbackTrailRamp[step])
+	}
+	return palette.Dead
+}
+
+// FadeColor is the color of a live cell step of steps into the fade-out
+// between two soups: from the alive color toward the dead color.
+func FadeColor(palette Palette, step, steps int) string {
+	if steps <= 0 || step >= steps {
+		return palette.Dead
+	}
+	if step < 0 {
+		step = 0
+	}
+	switch palette.Mode {
+	case ModeTrueColor:
+		return mixHexColor(palette.Alive, palette.Dead, float64(step)/float64(steps))
+	case ModeFallback:
+		return strconv.Itoa(fallbackFadeRamp[step*len(fallbackFadeRamp)/steps])
+	}
+	return palette.Dead
+}
+
+func mixHexColor(fromHex, toHex string, ratio float64) string {
+	from, okFrom := parseHexColor(fromHex)
+	to, okTo := parseHexColor(toHex)
+	if !okFrom || !okTo {
+		return toHex
+	}
+	var mixed [3]int
+	for i := range mixed {
+		mixed[i] = from[i] + int(float64(to[i]-from[i])*ratio+0.5)
+	}
+	return fmt.Sprintf("#%02X%02X%02X", mixed[0], mixed[1], mixed[2])
+}
+
+func RenderTrailCell(age, length int, palette Palette) string {
+	if age <= 0 {
+		return RenderCell(false, false, palette)
+	}
+	color := TrailColor(palette, age, length)
+	switch palette.Mode {
+	case ModeTrueColor:
+		rgb, ok := parseHexColor(color)
+		if !ok {
+			break
+		}
+		return fmt.Sprintf("\x1b[38;2;%d;%d;%dm█\x1b[0m", rgb[0], rgb[1], rgb[2])
+	case ModeFallback:
+		return "\x1b[38;5;" + color + "m█\x1b[0m"
+	}
+	return "░"
+}
+
+func parseHexColor(value string) ([3]int, bool) {
+	if len(value) != 7 || !strings.HasPrefix(value, "#") {
+		return [3]int{}, false
+	}
+	var rgb [3]int
+	for i := range rgb {
+		parsed, err := strconv.ParseUint(value[1+i*2:3+i*2], 16, 8)
+		if err != nil {
+			return [3]int{}, false
+		}
+		rgb[i] = int(parsed)
+	}
+	return rgb, true
+}
+
+func BuildFrameWithTrail(board engine.Board, previous *engine.Board, trail *Trail, status StatusBarData, palette Palette) string {
+	if trail == nil {
+		return BuildFrameWithHistory(board, previous, status, palette)
+	}
+	var b strings.Builder
+	for y := 0; y < board.Height(); y++ {
+		for x := 0; x < board.Width(); x++ {
+			isAlive := board.IsAlive(x, y)
+			if age := trail.Age(x, y); !isAlive && age > 0 {
+				b.WriteString(RenderTrailCell(age, trail.Length(), palette))
+				continue
+			}
+			wasAlive := previous != nil && previous.IsAlive(x, y)
+			b.WriteString(RenderCell(isAlive, wasAlive, palette))
+		}
+		b.WriteRune('\n')
+	}
+	b.WriteString(BuildStatusBar(status))
+	b.WriteRune('\n')
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"gol-on-cli/internal/engine"
+)
+
+func TestShouldFadeDeadCellOverConfiguredTrailLength(t *testing.T) {
+	board := engine.NewBoard(3, 3)
+	board.SetAlive(1, 1, true)
+	trail := NewTrail(3, 3, 3)
+	trail.Seed(board)
+
+	empty := engine.NewBoard(3, 3)
+	expected := []int{1, 2, 3, 0}
+	for generation, age := range expected {
+		trail.Advance(empty)
+		if got := trail.Age(1, 1); got != age {
+			t.Fatalf("expected age %d after generation %d, got %d", age, generation+1, got)
+		}
+	}
+}
+
+func TestShouldReportOnlyCellsWhoseTrailChanged(t *testing.T) {
+	board := engine.NewBoard(4, 1)
+	board.SetAlive(0, 0, true)
+	trail := NewTrail(2, 4, 1)
+	trail.Seed(board)
+
+	changed := trail.Advance(engine.NewBoard(4, 1))
+
+	if len(changed) != 1 || changed[0] != (TrailPoint{X: 0, Y: 0}) {
+		t.Fatalf("expected only the dying cell to change, got %v", changed)
+	}
+}
+
+func TestShouldInterpolateTrailColorsInTrueColorMode(t *testing.T) {
+	palette := SelectPalette(true)
+
+	first := TrailColor(palette, 1, 4)
+	middle := TrailColor(palette, 3, 4)
+
+	if first != palette.RecentlyDead {
+		t.Fatalf("expected first trail step to use recently-dead color, got %s", first)
+	}
+	if middle == palette.RecentlyDead || middle == palette.Dead || !strings.HasPrefix(middle, "#") {
+		t.Fatalf("expected interpolated hex color, got %s", middle)
+	}
+}
+
+func TestShouldStepTrailColorsInFallbackMode(t *testing.T) {
+	palette := SelectPalette(false)
+
+	first := TrailColor(palette, 1, 6)
+	last := TrailColor(palette, 6, 6)
+
+	if first == last {
+		t.Fatalf("expected stepped fallback colors to differ, got %s", first)
+	}
+	if strings.HasPrefix(first, "#") {
+		t.Fatalf("expected palette index in fallback mode, got %s", first)
+	}
+}
@@ -0,0 +1,223 @@
+// Package search runs batches of seeded soups on an unbounded plane, takes a
+// census of what each one leaves behind and keeps the interesting ones.
+package search
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/app"
+	"gol-on-cli/internal/engine"
+)
+
+const (
+	DefaultSoups          = 1000
+	DefaultMethuselahAge  = 3000
+	DefaultMaxGenerations = 20000
+	defaultSoupSize       = 16
+)
+
+// Find kinds.
+const (
+	FindRare       = "rare"
+	FindMethuselah = "methuselah"
+	// FindUnstable marks soups that never settled: guns, puffers, or
+	// anything still growing irregularly when the search gave up.
+	FindUnstable = "unstable"
+)
+
+type Options struct {
+	Seed           string
+	First          int
+	Soups          int
+	Workers        int
+	Soup           app.SoupSpec
+	MaxGenerations int
+	MethuselahAge  int
+	Census         analysis.CensusOptions
+	// Progress, when set, is called after each soup with the number done.
+	Progress func(done int)
+}
+
+// DefaultOptions searches 16x16 soups, the size Catagolue's C1 census uses.
+func DefaultOptions() Options {
+	soup := app.DefaultSoupSpec()
+	soup.Width, soup.Height = defaultSoupSize, defaultSoupSize
+	return Options{
+		Soups:          DefaultSoups,
+		Workers:        runtime.NumCPU(),
+		Soup:           soup,
+		MaxGenerations: DefaultMaxGenerations,
+		MethuselahAge:  DefaultMethuselahAge,
+		Census:         analysis.DefaultCensusOptions(),
+	}
+}
+
+// Soup is one searched soup: its ID, the starting window, how it ended and
+// what it left.
+type Soup struct {
+	ID     string
+	Board  engine.Board
+	Result analysis.Result
+	Census analysis.Census
+}
+
+// Find is something worth keeping. Code is the rare object's apgcode, or
+// the class of an unstable soup.
+type Find struct {
+	Kind     string `json:"kind"`
+	SoupID   string `json:"soup"`
+	Code     string `json:"apgcode,omitempty"`
+	Count    int    `json:"count,omitempty"`
+	Lifespan int    `json:"lifespan"`
+}
+
+// Report sums up a search. Soups is how many finished, which is fewer than
+// asked for when the search was cancelled.
+type Report struct {
+	Seed     string          `json:"seed"`
+	First    int             `json:"first"`
+	Soups    int             `json:"soups"`
+	Settings Settings        `json:"settings"`
+	Census   analysis.Census `json:"census"`
+	Finds    []Find          `json:"finds"`
+	// Boards holds the starting window of every soup with a find, by ID.
+	Boards map[string]engine.Board `json:"-"`
+}
+
+// Settings are the options besides the seed that decide which soups a
+// search runs and what it keeps, so a report can be reproduced.
+type Settings struct {
+	Rule           string  `json:"rule"`
+	SoupSize       string  `json:"soup_size"`
+	Density        float64 `json:"soup_density"`
+	Symmetry       string  `json:"soup_symmetry"`
+	Neighborhood   string  `json:"neighborhood"`
+	MaxGenerations int     `json:"max_generations"`
+	MethuselahAge  int     `json:"methuselah_age"`
+}
+
+// Run searches soups First to First+Soups-1 of Seed across Workers
+// goroutines. The report does not depend on the number of workers or the
+// order soups finish in.
+func Run(ctx context.Context, options Options) Report {
+	workers := max(1, options.Workers)
+	jobs := make(chan int)
+	results := make(chan Soup)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results <- RunSoup(app.SoupID(options.Seed, index), options)
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for index := options.First; index < options.First+options.Soups; index++ {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	report := newReport(options)
+	counts := map[string]int{}
+	for soup := range results {
+		report.add(soup, counts, options.MethuselahAge)
+		if options.Progress != nil {
+			options.Progress(report.Soups)
+		}
+	}
+	sort.SliceStable(report.Finds, func(i, j int) bool {
+		_, a, _ := app.ParseSoupID(report.Finds[i].SoupID)
+		_, b, _ := app.ParseSoupID(report.Finds[j].SoupID)
+		return a < b
+	})
+	report.Census = analysis.NewCensus(counts)
+	return report
+}
+
+// RunSoup places soup id at the origin of an unbounded plane and runs it
+// until it settles or MaxGenerations pass.
+func RunSoup(id string, options Options) Soup {
+	width, height := options.Soup.Width, options.Soup.Height
+	if width == 0 && height == 0 {
+		width, height = defaultSoupSize, defaultSoupSize
+	}
+	board := options.Soup.Generate(app.SoupRand(id), width, height)
+	analyzeOptions := analysis.DefaultOptions()
+	analyzeOptions.Rule = options.Census.Rule
+	analyzeOptions.MaxGenerations = options.MaxGenerations
+	result := analysis.Analyze(engine.UniverseFromBoard(board, 0, 0), analyzeOptions)
+	return Soup{
+		ID:     id,
+		Board:  board,
+		Result: result,
+		Census: analysis.TakeUniverseCensus(result.Final, options.Census),
+	}
+}
+
+// Finds lists what makes a soup worth keeping: objects outside the common
+// set, a long life, or never settling.
+func (s Soup) Finds(methuselahAge int) []Find {
+	var finds []Find
+	switch s.Result.Class {
+	case analysis.ClassGun, analysis.ClassPuffer, analysis.ClassUnknown:
+		finds = append(finds, Find{Kind: FindUnstable, SoupID: s.ID, Code: string(s.Result.Class), Lifespan: s.Result.Generations})
+	default:
+		if s.Result.Stabilization >= methuselahAge {
+			finds = append(finds, Find{Kind: FindMethuselah, SoupID: s.ID, Lifespan: s.Result.Stabilization})
+		}
+	}
+	for _, entry := range s.Census.Objects {
+		if !analysis.IsCommonObject(entry.Code) {
+			finds = append(finds, Find{Kind: FindRare, SoupID: s.ID, Code: entry.Code, Count: entry.Count, Lifespan: s.Result.Stabilization})
+		}
+	}
+	return finds
+}
+
+func newReport(options Options) Report {
+	return Report{
+		Seed:  options.Seed,
+		First: options.First,
+		Settings: Settings{
+			Rule:           options.Census.Rule.String(),
+			SoupSize:       options.Soup.Size(),
+			Density:        options.Soup.Density,
+			Symmetry:       string(options.Soup.Symmetry),
+			Neighborhood:   options.Census.Neighborhood.String(),
+			MaxGenerations: options.MaxGenerations,
+			MethuselahAge:  options.MethuselahAge,
+		},
+		Finds:  []Find{},
+		Boards: map[string]engine.Board{},
+	}
+}
+
+// add folds one finished soup into the report.
+func (r *Report) add(soup Soup, counts map[string]int, methuselahAge int) {
+	r.Soups++
+	for _, entry := range soup.Census.Objects {
+		counts[entry.Code] += entry.Count
+	}
+	if finds := soup.Finds(methuselahAge); len(finds) > 0 {
+		r.Finds = append(r.Finds, finds...)
+		r.Boards[soup.ID] = soup.Board
+	}
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gol-on-cli/internal/analysis"
+	"gol-on-cli/internal/engine"
+)
+
+func smallOptions() Options {
+	options := DefaultOptions()
+	options.Seed = "k_test"
+	options.Soups = 6
+	options.Soup.Width, options.Soup.Height = 8, 8
+	return options
+}
+
+func TestShouldReportTheSameSearchForAnyNumberOfWorkers(t *testing.T) {
+	options := smallOptions()
+	options.Workers = 1
+	serial := Run(context.Background(), options)
+	options.Workers = 4
+	parallel := Run(context.Background(), options)
+
+	if serial.Soups != 6 || serial.Census.Total == 0 {
+		t.Fatalf("expected six soups with objects, got %+v", serial)
+	}
+	if !reflect.DeepEqual(serial.Census, parallel.Census) || !reflect.DeepEqual(serial.Finds, parallel.Finds) {
+		t.Fatalf("expected worker count not to matter:\n%+v\n%+v", serial, parallel)
+	}
+}
+
+func TestShouldStopDispatchingSoupsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := Run(ctx, smallOptions())
+
+	if report.Soups >= 6 {
+		t.Fatalf("expected a cancelled search to stop early, ran %d soups", report.Soups)
+	}
+}
+
+func TestShouldFindRareObjectsMethuselahsAndUnstableSoups(t *testing.T) {
+	soup := Soup{
+		ID:     "k_test#3",
+		Result: analysis.Result{Class: analysis.ClassMethuselah, Stabilization: 4000},
+		Census: analysis.NewCensus(map[string]int{"xs4_33": 3, "xp15_4r4z4r4": 1}),
+	}
+	finds := soup.Finds(3000)
+	want := []Find{
+		{Kind: FindMethuselah, SoupID: "k_test#3", Lifespan: 4000},
+		{Kind: FindRare, SoupID: "k_test#3", Code: "xp15_4r4z4r4", Count: 1, Lifespan: 4000},
+	}
+	if !reflect.DeepEqual(finds, want) {
+		t.Fatalf("unexpected finds %+v", finds)
+	}
+
+	soup.Result = analysis.Result{Class: analysis.ClassUnknown, Generations: 20000}
+	soup.Census = analysis.NewCensus(map[string]int{"xs4_33": 1})
+	if finds := soup.Finds(3000); len(finds) != 1 || finds[0].Kind != FindUnstable || finds[0].Code != "unknown" {
+		t.Fatalf("expected an unstable find, got %+v", finds)
+	}
+}
+
+func TestShouldReproduceEachSoupFromItsID(t *testing.T) {
+	options := smallOptions()
+	first := RunSoup("k_test#2", options)
+	again := RunSoup("k_test#2", options)
+
+	if !reflect.DeepEqual(first.Census, again.Census) || first.Result.Stabilization != again.Result.Stabilization {
+		t.Fatalf("expected soup k_test#2 to run the same way twice")
+	}
+}
+
+func TestShouldWriteReportFiles(t *testing.T) {
+	dir := t.TempDir()
+	soup := engine.NewBoard(3, 3)
+	soup.SetAlive(1, 0, true)
+	report := Report{
+		Seed:     "k_test",
+		Soups:    1,
+		Settings: Settings{Rule: "B3/S23"},
+		Census:   analysis.NewCensus(map[string]int{"xs4_33": 2, "xs8_69ic": 1}),
+		Finds:    []Find{{Kind: FindRare, SoupID: "k_test#0", Code: "xs8_69ic", Count: 1, Lifespan: 120}},
+		Boards:   map[string]engine.Board{"k_test#0": soup},
+	}
+
+	if err := report.Write(dir); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	finds, err := os.ReadFile(filepath.Join(dir, "finds.csv"))
+	if err != nil || string(finds) != "kind,soup,apgcode,count,lifespan\nrare,k_test#0,xs8_69ic,1,120\n" {
+		t.Fatalf("unexpected finds.csv %q %v", finds, err)
+	}
+	census, err := os.ReadFile(filepath.Join(dir, "census.csv"))
+	if err != nil || !strings.Contains(string(census), "xs4_33,block,2") {
+		t.Fatalf("unexpected census.csv %q %v", census, err)
+	}
+	summary, err := os.ReadFile(filepath.Join(dir, "summary.json"))
+	if err != nil || !strings.Contains(string(summary), `"seed": "k_test"`) || !strings.Contains(string(summary), `"rule": "B3/S23"`) {
+		t.Fatalf("unexpected summary.json %s %v", summary, err)
+	}
+	rle, err := os.ReadFile(filepath.Join(dir, "soups", "k_test_0.rle"))
+	if err != nil || !strings.Contains(string(rle), "#N k_test#0") || !strings.Contains(string(rle), "#C rare xs8_69ic x1") {
+		t.Fatalf("unexpected soup file %q %v", rle, err)
+	}
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gol-on-cli/internal/pattern"
+)
+
+// Write saves the report under dir: census.csv with every object found,
+// finds.csv, summary.json with the settings needed to repeat the search,
+// and soups/<seed>_<index>.rle for each soup with a find.
+func (r Report) Write(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "soups"), 0o755); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "census.csv"), func(file *os.File) error { return r.Census.WriteCSV(file) }); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "finds.csv"), r.writeFindsCSV); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, "summary.json"), func(file *os.File) error {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}); err != nil {
+		return err
+	}
+	for id, board := range r.Boards {
+		var notes []string
+		for _, find := range r.Finds {
+			if find.SoupID == id {
+				notes = append(notes, find.String())
+			}
+		}
+		rle := pattern.Pattern{Board: board, Name: id, Rule: r.Settings.Rule, Comments: notes}
+		encoded, err := pattern.Encode(rle, pattern.FormatRLE)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "soups", SoupFileName(id)), []byte(encoded), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r Report) writeFindsCSV(file *os.File) error {
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"kind", "soup", "apgcode", "count", "lifespan"}); err != nil {
+		return err
+	}
+	for _, find := range r.Finds {
+		if err := writer.Write([]string{find.Kind, find.SoupID, find.Code, strconv.Itoa(find.Count), strconv.Itoa(find.Lifespan)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeFile(path string, write func(file *os.File) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SoupFileName turns a soup ID into a file name: "k_abc#12" becomes
+// "k_abc_12.rle".
+func SoupFileName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '#' || r == ':' {
+			return '_'
+		}
+		return r
+	}, id)
+	return name + ".rle"
+}
+
+func (f Find) String() string {
+	switch f.Kind {
+	case FindRare:
+		return fmt.Sprintf("rare %s x%d", f.Code, f.Count)
+	case FindMethuselah:
+		return fmt.Sprintf("methuselah lasting %d generations", f.Lifespan)
+	}
+	return fmt.Sprintf("unstable (%s) after %d generations", f.Code, f.Lifespan)
+}